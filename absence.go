@@ -0,0 +1,166 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// SortedTree is a merkle tree over items sorted in increasing byte order without repetitions,
+// whose absent values can be proven by showing the two adjacent leaves around them.
+// Absence proofs are only sound when the tree was built sorted, which a SortedTree always is:
+// a verifier trusts the publisher of the root to have committed to sorted leaves.
+type SortedTree struct {
+	t      *Tree
+	leaves [][]byte
+}
+
+// NewSortedTree sorts and deduplicates the items and hashes them into a SortedTree.
+// The options configure the hash function as for NewHasher.
+func NewSortedTree(items [][]byte, opts ...Option) *SortedTree {
+	return NewHasher(opts...).NewSortedTree(items)
+}
+
+// NewSortedTree sorts and deduplicates the items and hashes them into a SortedTree using the Hasher's hash function.
+func (h *Hasher) NewSortedTree(items [][]byte) *SortedTree {
+	leaves := make([][]byte, len(items))
+	for i, item := range items {
+		leaves[i] = append([]byte(nil), item...)
+	}
+	sort.Slice(leaves, func(i, j int) bool { return bytes.Compare(leaves[i], leaves[j]) < 0 })
+	unique := leaves[:0]
+	for i, leaf := range leaves {
+		if i == 0 || !bytes.Equal(leaf, leaves[i-1]) {
+			unique = append(unique, leaf)
+		}
+	}
+	return &SortedTree{t: h.NewTree(unique), leaves: unique}
+}
+
+// Root returns the root hash of the tree.
+func (s *SortedTree) Root() []byte {
+	return s.t.Root()
+}
+
+// Len returns the number of leaves of the tree.
+func (s *SortedTree) Len() int {
+	return len(s.leaves)
+}
+
+// search returns the index of the first leaf that is not smaller than value and whether it is equal to value.
+func (s *SortedTree) search(value []byte) (int, bool) {
+	i := sort.Search(len(s.leaves), func(i int) bool { return bytes.Compare(s.leaves[i], value) >= 0 })
+	return i, i < len(s.leaves) && bytes.Equal(s.leaves[i], value)
+}
+
+// Prove returns the inclusion proof of value.
+// This errors with ErrLeafNotFound when value is not a leaf of the tree.
+func (s *SortedTree) Prove(value []byte) (InclusionProof, error) {
+	i, ok := s.search(value)
+	if !ok {
+		return InclusionProof{}, ErrLeafNotFound
+	}
+	return s.neighbor(i).InclusionProof, nil
+}
+
+// Neighbor is a leaf of a sorted tree next to an absent value, with its inclusion proof.
+type Neighbor struct {
+	Leaf []byte
+	InclusionProof
+}
+
+// AbsenceProof proves that a value is not a leaf of a sorted tree of TreeSize leaves with the
+// leaves just before and after it, Left being nil when the value comes before every leaf and
+// Right being nil when it comes after every leaf. The proof of the empty tree has no neighbor.
+type AbsenceProof struct {
+	TreeSize int
+	Left     *Neighbor
+	Right    *Neighbor
+}
+
+// ProveAbsence returns the proof that value is not a leaf of the tree.
+// This errors with ErrKeyExists when value is a leaf of the tree.
+func (s *SortedTree) ProveAbsence(value []byte) (AbsenceProof, error) {
+	i, ok := s.search(value)
+	if ok {
+		return AbsenceProof{}, fmt.Errorf("%w: value is the leaf at index %v", ErrKeyExists, i)
+	}
+	p := AbsenceProof{TreeSize: len(s.leaves)}
+	if i > 0 {
+		left := s.neighbor(i - 1)
+		p.Left = &left
+	}
+	if i < len(s.leaves) {
+		right := s.neighbor(i)
+		p.Right = &right
+	}
+	return p, nil
+}
+
+func (s *SortedTree) neighbor(i int) Neighbor {
+	path, _ := s.t.Proof(i)
+	return Neighbor{
+		Leaf:           s.leaves[i],
+		InclusionProof: InclusionProof{LeafIndex: i, TreeSize: len(s.leaves), Path: path},
+	}
+}
+
+// VerifyAbsence verifies that value is not a leaf of the sorted tree whose root is root:
+// the neighbors are included in the tree, adjacent, or first or last when one of them is missing,
+// and value sorts strictly between them.
+// This errors with ErrKeyExists when a neighbor is value itself, ErrMalformedProof when the
+// neighbors do not bracket value or are not adjacent leaves of a tree of the proof's size and
+// otherwise like InclusionProof.Verify.
+func VerifyAbsence(root, value []byte, p AbsenceProof) error {
+	return defaultHasher.VerifyAbsence(root, value, p)
+}
+
+// VerifyAbsence verifies an AbsenceProof using the Hasher's hash function.
+func (h *Hasher) VerifyAbsence(root, value []byte, p AbsenceProof) error {
+	return h.checkAbsence(root, value, p, func(n *Neighbor) []byte { return n.Leaf })
+}
+
+// checkAbsence verifies an absence proof whose neighbors are ordered by their Leaf and included
+// in the tree as the leaf returned by leaf.
+func (h *Hasher) checkAbsence(root, value []byte, p AbsenceProof, leaf func(n *Neighbor) []byte) error {
+	if p.TreeSize == 0 {
+		if p.Left != nil || p.Right != nil {
+			return fmt.Errorf("%w: neighbors in the empty tree", ErrMalformedProof)
+		}
+		if !equalDigest(root, h.empty) {
+			return ErrRootMismatch
+		}
+		return nil
+	}
+
+	switch {
+	case p.Left == nil && p.Right == nil:
+		return fmt.Errorf("%w: no neighbor", ErrMalformedProof)
+	case p.Left == nil && p.Right.LeafIndex != 0:
+		return fmt.Errorf("%w: right neighbor at index %v is not the first leaf", ErrMalformedProof, p.Right.LeafIndex)
+	case p.Right == nil && p.Left.LeafIndex != p.TreeSize-1:
+		return fmt.Errorf("%w: left neighbor at index %v is not the last of %v leaves", ErrMalformedProof, p.Left.LeafIndex, p.TreeSize)
+	case p.Left != nil && p.Right != nil && p.Right.LeafIndex != p.Left.LeafIndex+1:
+		return fmt.Errorf("%w: neighbors at indices %v and %v are not adjacent", ErrMalformedProof, p.Left.LeafIndex, p.Right.LeafIndex)
+	}
+
+	for _, n := range []*Neighbor{p.Left, p.Right} {
+		if n == nil {
+			continue
+		}
+		if n.TreeSize != p.TreeSize {
+			return fmt.Errorf("%w: neighbor proven in a tree of %v leaves, expected %v", ErrMalformedProof, n.TreeSize, p.TreeSize)
+		}
+		c := bytes.Compare(n.Leaf, value)
+		if c == 0 {
+			return fmt.Errorf("%w: value is the leaf at index %v", ErrKeyExists, n.LeafIndex)
+		}
+		if (c < 0) != (n == p.Left) {
+			return fmt.Errorf("%w: neighbor at index %v does not bracket value", ErrMalformedProof, n.LeafIndex)
+		}
+		if err := h.VerifyInclusion(root, leaf(n), n.InclusionProof); err != nil {
+			return err
+		}
+	}
+	return nil
+}
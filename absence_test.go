@@ -0,0 +1,44 @@
+package merkle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyAbsence(t *testing.T) {
+	s := NewSortedTree([][]byte{[]byte("d"), []byte("b"), []byte("e"), []byte("a"), []byte("b")})
+	root := s.Root()
+	for _, v := range []string{"", "aa", "c", "da", "f"} {
+		p, err := s.ProveAbsence([]byte(v))
+		if err != nil {
+			t.Fatalf("ProveAbsence(%q): %v", v, err)
+		}
+		if err := VerifyAbsence(root, []byte(v), p); err != nil {
+			t.Errorf("VerifyAbsence(%q): %v", v, err)
+		}
+	}
+	if _, err := s.ProveAbsence([]byte("b")); !errors.Is(err, ErrKeyExists) {
+		t.Errorf("ProveAbsence of a leaf: got %v, want ErrKeyExists", err)
+	}
+}
+
+// TestVerifyAbsenceForgedNeighbor relabels the proof of d, at index 2 of [a b d e], as the proof
+// of index 1 so that a and d look adjacent around b, which is a leaf of the tree.
+func TestVerifyAbsenceForgedNeighbor(t *testing.T) {
+	s := NewSortedTree([][]byte{[]byte("a"), []byte("b"), []byte("d"), []byte("e")})
+	root := s.Root()
+	p, err := s.ProveAbsence([]byte("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := s.neighbor(0)
+	d := *p.Right
+	d.LeafIndex = 1
+	forged := AbsenceProof{TreeSize: 4, Left: &a, Right: &d}
+	if err := VerifyAbsence(root, []byte("b"), forged); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("forged absence of b: got %v, want ErrMalformedProof", err)
+	}
+	if err := d.Verify(root, []byte("d")); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("relabeled inclusion of d: got %v, want ErrMalformedProof", err)
+	}
+}
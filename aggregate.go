@@ -0,0 +1,91 @@
+package merkle
+
+import "fmt"
+
+// AggregateRoots returns the root of the super-tree whose items are the roots of shard trees, such
+// as one tree per day or per tenant. The shard roots are hashed as leaves, with the leaf prefix,
+// so that a shard root is never taken for an interior node of the super-tree.
+func AggregateRoots(shardRoots [][]byte) []byte {
+	return defaultHasher.AggregateRoots(shardRoots)
+}
+
+// AggregateRoots returns the root of the super-tree over shardRoots using the Hasher's hash function.
+func (h *Hasher) AggregateRoots(shardRoots [][]byte) []byte {
+	return h.Root(shardRoots)
+}
+
+// ComposedProof proves that a leaf is included in the shard at ShardIndex, whose root is
+// ShardRoot, and that ShardRoot is included in a super-tree built by AggregateRoots.
+type ComposedProof struct {
+	ShardIndex int
+	ShardRoot  []byte
+	Inner      InclusionProof // the leaf in its shard
+	Outer      InclusionProof // the shard root in the super-tree
+}
+
+// ComposeProof returns the composed proof of the leaf at index in the shard at shardIndex of
+// shards, whose roots are aggregated in that order.
+// This errors with ErrHashMismatch when the shards do not all hash the same way, with
+// ErrIndexOutOfBounds when shardIndex is out of bounds and like Tree.Proof for the leaf.
+func ComposeProof(shards []*Tree, shardIndex, index int) (ComposedProof, error) {
+	if shardIndex < 0 || shardIndex >= len(shards) {
+		return ComposedProof{}, fmt.Errorf("%w: shard %v of %v", ErrIndexOutOfBounds, shardIndex, len(shards))
+	}
+	h := shards[0].h
+	roots := make([][]byte, len(shards))
+	for i, s := range shards {
+		if !h.sameHashing(s.h) {
+			return ComposedProof{}, fmt.Errorf("%w: shard %v is built with another hasher than shard 0", ErrHashMismatch, i)
+		}
+		if i != shardIndex {
+			roots[i] = s.Root()
+		}
+	}
+	inner, root, err := shards[shardIndex].proveWithRoot(index)
+	if err != nil {
+		return ComposedProof{}, err
+	}
+	roots[shardIndex] = root
+	outer, err := h.Prove(roots, shardIndex)
+	if err != nil {
+		return ComposedProof{}, err
+	}
+	return ComposedProof{ShardIndex: shardIndex, ShardRoot: roots[shardIndex], Inner: inner, Outer: outer}, nil
+}
+
+// Verify verifies that leaf is included in the super-tree whose root is superRoot.
+// This errors like Hasher.VerifyComposed.
+func (p ComposedProof) Verify(superRoot, leaf []byte) error {
+	return defaultHasher.VerifyComposed(superRoot, leaf, p)
+}
+
+// VerifyComposed verifies a composed proof using the Hasher's hash function for both layers, so
+// that a proof whose layers were hashed differently does not verify.
+// This errors with ErrIndexOutOfBounds when ShardIndex is not the index of the outer proof, and
+// like VerifyInclusion for the inner proof against ShardRoot and the outer proof against superRoot,
+// naming the layer at fault. The sides of the outer path are checked against ShardIndex, so the
+// proof of a leaf of one shard does not verify as a proof for another.
+func (h *Hasher) VerifyComposed(superRoot, leaf []byte, p ComposedProof) error {
+	if p.ShardIndex != p.Outer.LeafIndex {
+		return fmt.Errorf("%w: shard %v, the outer proof is for index %v", ErrIndexOutOfBounds, p.ShardIndex, p.Outer.LeafIndex)
+	}
+	if err := h.VerifyInclusion(p.ShardRoot, leaf, p.Inner); err != nil {
+		return fmt.Errorf("merkle: shard %v: %w", p.ShardIndex, err)
+	}
+	if err := h.VerifyInclusion(superRoot, p.ShardRoot, p.Outer); err != nil {
+		return fmt.Errorf("merkle: super-tree: %w", err)
+	}
+	return nil
+}
+
+// proveWithRoot returns the inclusion proof of the leaf at index i with the root it leads to,
+// read under the same lock.
+func (t *Tree) proveWithRoot(i int) (InclusionProof, []byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	path, err := t.proof(i)
+	if err != nil {
+		return InclusionProof{}, nil, err
+	}
+	return InclusionProof{LeafIndex: i, TreeSize: t.leafCount(), Path: path}, append([]byte(nil), t.root()...), nil
+}
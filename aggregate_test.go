@@ -0,0 +1,54 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newTestShards(sizes ...int) []*Tree {
+	shards := make([]*Tree, len(sizes))
+	for s, n := range sizes {
+		items := make([][]byte, n)
+		for i := range items {
+			items[i] = []byte(fmt.Sprintf("shard %v item %v", s, i))
+		}
+		shards[s] = NewTree(items)
+	}
+	return shards
+}
+
+func TestComposedProof(t *testing.T) {
+	shards := newTestShards(3, 1, 4)
+	roots := make([][]byte, len(shards))
+	for s, shard := range shards {
+		roots[s] = shard.Root()
+	}
+	super := AggregateRoots(roots)
+	for s, shard := range shards {
+		for i := 0; i < shard.LeafCount(); i++ {
+			p, err := ComposeProof(shards, s, i)
+			if err != nil {
+				t.Fatalf("ComposeProof(%v, %v): %v", s, i, err)
+			}
+			if err := p.Verify(super, []byte(fmt.Sprintf("shard %v item %v", s, i))); err != nil {
+				t.Errorf("Verify(%v, %v): %v", s, i, err)
+			}
+		}
+	}
+}
+
+// TestComposedProofRelabeledShard presents the proof of a leaf of shard 2 as a proof for shard 3,
+// whose outer path has the same length.
+func TestComposedProofRelabeledShard(t *testing.T) {
+	shards := newTestShards(2, 2, 2, 2)
+	super := AggregateRoots([][]byte{shards[0].Root(), shards[1].Root(), shards[2].Root(), shards[3].Root()})
+	p, err := ComposeProof(shards, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ShardIndex, p.Outer.LeafIndex = 3, 3
+	if err := p.Verify(super, []byte("shard 2 item 0")); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("relabeled shard: got %v, want ErrMalformedProof", err)
+	}
+}
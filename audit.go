@@ -0,0 +1,98 @@
+package merkle
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+)
+
+// AuditSample is a leaf picked by SampleAudit with its inclusion proof. The auditor checks the
+// leaf hash against the item it fetches from the operator, LeafHashAt(LeafIndex, item).
+type AuditSample struct {
+	LeafHash []byte
+	InclusionProof
+}
+
+// SampleAudit picks k distinct leaves of the tree uniformly at random and returns them with their
+// inclusion proofs, in increasing index order. The randomness is read from rng, crypto/rand when
+// nil, so that a fixed reader gives a reproducible sample.
+// A k larger than the number of leaves samples every leaf, a k <= 0 samples none and reads nothing.
+// This errors with ErrEmptyTree when the tree has no items and with the error of rng, wrapped.
+func SampleAudit(tree *Tree, k int, rng io.Reader) ([]AuditSample, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	if rng == nil {
+		rng = rand.Reader
+	}
+	tree.mu.RLock()
+	defer tree.mu.RUnlock()
+
+	n := tree.leafCount()
+	if n == 0 {
+		return nil, ErrEmptyTree
+	}
+	if k > n {
+		k = n
+	}
+	indices, err := sampleIndices(n, k, rng)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]AuditSample, k)
+	for j, i := range indices {
+		path, _ := tree.proof(i)
+		samples[j] = AuditSample{
+			LeafHash:       append([]byte(nil), tree.levels[0][i]...),
+			InclusionProof: InclusionProof{LeafIndex: i, TreeSize: n, Path: path},
+		}
+	}
+	return samples, nil
+}
+
+// sampleIndices returns k distinct indices below n in increasing order, drawn by the first k
+// steps of a Fisher-Yates shuffle of which only the swapped positions are kept.
+func sampleIndices(n, k int, rng io.Reader) ([]int, error) {
+	swapped := make(map[int]int, k)
+	at := func(i int) int {
+		if v, ok := swapped[i]; ok {
+			return v
+		}
+		return i
+	}
+	indices := make([]int, k)
+	for j := range indices {
+		r, err := rand.Int(rng, big.NewInt(int64(n-j)))
+		if err != nil {
+			return nil, fmt.Errorf("merkle: sampling leaves: %w", err)
+		}
+		m := j + int(r.Int64())
+		indices[j] = at(m)
+		swapped[m] = at(j)
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
+
+// VerifySamples verifies every sample returned by SampleAudit against root.
+// This errors for the first sample that does not verify, naming it and wrapping the error of
+// VerifyInclusion or of VerifyLeafHashE.
+func VerifySamples(root []byte, samples []AuditSample) error {
+	return defaultHasher.VerifySamples(root, samples)
+}
+
+// VerifySamples verifies audit samples using the Hasher's hash function.
+func (h *Hasher) VerifySamples(root []byte, samples []AuditSample) error {
+	for j, s := range samples {
+		err := h.checkInclusion(root, s.InclusionProof)
+		if err == nil {
+			err = h.VerifyLeafHashE(root, s.LeafHash, s.LeafIndex, s.Path)
+		}
+		if err != nil {
+			return fmt.Errorf("merkle: sample %v of leaf %v: %w", j, s.LeafIndex, err)
+		}
+	}
+	return nil
+}
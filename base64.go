@@ -0,0 +1,78 @@
+package merkle
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// proofBase64 is unpadded base64url, decoding only the canonical encoding of each input.
+var proofBase64 = base64.RawURLEncoding.Strict()
+
+// EncodeProofBase64 encodes an audit path produced with the default hash function as unpadded
+// base64url over the layout of MarshalProof, for use in URLs and QR codes.
+// This errors like MarshalProof.
+func EncodeProofBase64(path []AuditHash) (string, error) {
+	return defaultHasher.EncodeProofBase64(path)
+}
+
+// EncodeProofBase64 encodes an audit path as unpadded base64url over the layout of Hasher.MarshalProof.
+func (h *Hasher) EncodeProofBase64(path []AuditHash) (string, error) {
+	data, err := h.MarshalProof(path)
+	if err != nil {
+		return "", err
+	}
+	return proofBase64.EncodeToString(data), nil
+}
+
+// DecodeProofBase64 decodes an audit path encoded by EncodeProofBase64 for the default hash function.
+func DecodeProofBase64(s string) ([]AuditHash, error) {
+	return defaultHasher.DecodeProofBase64(s)
+}
+
+// DecodeProofBase64 decodes an audit path encoded by EncodeProofBase64.
+// This errors with ErrMalformedProof when s is padded, uses the standard base64 alphabet or is
+// not the canonical unpadded base64url encoding of some data, and otherwise like UnmarshalProof.
+func (h *Hasher) DecodeProofBase64(s string) ([]AuditHash, error) {
+	data, err := decodeBase64URL(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	return h.UnmarshalProof(data)
+}
+
+// EncodeRootBase64 encodes a root hash as unpadded base64url.
+func EncodeRootBase64(root []byte) string {
+	return proofBase64.EncodeToString(root)
+}
+
+// DecodeRootBase64 decodes a root hash encoded by EncodeRootBase64 for the default hash function.
+func DecodeRootBase64(s string) ([]byte, error) {
+	return defaultHasher.DecodeRootBase64(s)
+}
+
+// DecodeRootBase64 decodes a root hash encoded by EncodeRootBase64.
+// This errors with ErrInvalidHash when s is not canonical unpadded base64url, as for
+// DecodeProofBase64, or does not hold a digest of the Hasher's size.
+func (h *Hasher) DecodeRootBase64(s string) ([]byte, error) {
+	root, err := decodeBase64URL(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	if len(root) != h.Size() {
+		return nil, fmt.Errorf("%w: %v bytes, expected %v", ErrInvalidHash, len(root), h.Size())
+	}
+	return root, nil
+}
+
+// decodeBase64URL decodes unpadded base64url, telling padded and standard alphabet inputs apart
+// from other invalid inputs.
+func decodeBase64URL(s string) ([]byte, error) {
+	if strings.Contains(s, "=") {
+		return nil, fmt.Errorf("padded base64, expected unpadded base64url")
+	}
+	if strings.ContainsAny(s, "+/") {
+		return nil, fmt.Errorf("standard base64 alphabet, expected base64url")
+	}
+	return proofBase64.DecodeString(s)
+}
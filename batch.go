@@ -0,0 +1,106 @@
+package merkle
+
+import "fmt"
+
+// ProofWithLeaf is a leaf together with its inclusion proof.
+type ProofWithLeaf struct {
+	Leaf  []byte
+	Proof InclusionProof
+}
+
+// nodeCoord identifies a node by the size of its tree, its level and its index within the level.
+type nodeCoord struct {
+	size, level, index int
+}
+
+// VerifyBatch verifies every proof against root and returns the error of each proof, nil when it verifies,
+// the errors being the ones of InclusionProof.Verify.
+// Interior nodes of the proofs that verified are remembered, so a later proof reaching one of
+// them with the same hash is accepted without hashing further up. It is safe for concurrent use,
+// each call keeping its own memo.
+func VerifyBatch(root []byte, proofs []ProofWithLeaf) []error {
+	return defaultHasher.VerifyBatch(root, proofs)
+}
+
+// VerifyBatch verifies every proof against root using the Hasher's hash function.
+func (h *Hasher) VerifyBatch(root []byte, proofs []ProofWithLeaf) []error {
+	errs := make([]error, len(proofs))
+	memo := make(map[nodeCoord][]byte)
+	for i, p := range proofs {
+		errs[i] = h.verifyMemo(root, p, memo)
+	}
+	return errs
+}
+
+// verifyMemo verifies one proof, stopping at the first node found in memo, and adds the nodes
+// of the proof to memo when it verifies.
+func (h *Hasher) verifyMemo(root []byte, p ProofWithLeaf, memo map[nodeCoord][]byte) error {
+	index, size, path := p.Proof.LeafIndex, p.Proof.TreeSize, p.Proof.Path
+	if err := h.checkTreeSize(size); err != nil {
+		return err
+	}
+	if err := h.checkPathLen(len(path)); err != nil {
+		return err
+	}
+	if err := h.checkLeafSize(len(p.Leaf)); err != nil {
+		return err
+	}
+	if index < 0 || index >= size {
+		return indexError(index, size)
+	}
+	if want := ProofLen(index, size); len(path) != want {
+		return fmt.Errorf("%w: %v entries for index %v of %v, expected %v", ErrBadPathLength, len(path), index, size, want)
+	}
+	digestSize := h.Size()
+	if len(root) != digestSize {
+		return fmt.Errorf("%w: root has size %v, expected %v", ErrBadHashSize, len(root), digestSize)
+	}
+	for j, entry := range path {
+		if len(entry.Val) != digestSize {
+			return fmt.Errorf("%w: entry %v has size %v, expected %v", ErrBadHashSize, j, len(entry.Val), digestSize)
+		}
+	}
+
+	type step struct {
+		node, sibling nodeCoord
+		right         bool
+	}
+	steps := make([]step, 0, len(path))
+	walkPath(index, size, func(level, node, sibling int) {
+		steps = append(steps, step{nodeCoord{size, level, node}, nodeCoord{size, level, sibling}, sibling > node})
+	})
+	// The coordinates are only meaningful when the path matches the shape of the tree.
+	for j, s := range steps {
+		if path[j].RightOperator != s.right {
+			return ErrRootMismatch
+		}
+	}
+
+	d := h.LeafHashAt(index, p.Leaf)
+	seen := make(map[nodeCoord][]byte, 2*len(steps))
+	known := false
+	for j, s := range steps {
+		if m, ok := memo[s.node]; ok {
+			if !equalDigest(m, d) {
+				return ErrRootMismatch
+			}
+			known = true
+			break
+		}
+		seen[s.node] = d
+		seen[s.sibling] = path[j].Val
+		if s.right {
+			d = h.NodeHash(d, path[j].Val)
+		} else {
+			d = h.NodeHash(path[j].Val, d)
+		}
+	}
+	if !known && !equalDigest(root, d) {
+		return ErrRootMismatch
+	}
+
+	for c, node := range seen {
+		memo[c] = node
+	}
+	return nil
+}
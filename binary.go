@@ -0,0 +1,205 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// inclusionHeaderSize is the size of the leaf index and tree size fields of an encoded inclusion proof.
+const inclusionHeaderSize = 8 + 8
+
+// Digest is a root or node hash encoded as lowercase hex by its text marshaling, for example in
+// JSON, YAML or TOML documents.
+type Digest []byte
+
+// MarshalText encodes the digest as lowercase hex.
+func (d Digest) MarshalText() ([]byte, error) {
+	return []byte(hexify(d)), nil
+}
+
+// UnmarshalText decodes a hex encoded digest, the digest size is checked by the verifiers.
+// This errors with ErrInvalidHash when the text is not valid hex or is longer than 255 bytes.
+func (d *Digest) UnmarshalText(text []byte) error {
+	if len(text) > 2*maxHashSize {
+		return fmt.Errorf("%w: hash of %v hex characters", ErrInvalidHash, len(text))
+	}
+	v, err := unhexify(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	*d = v
+	return nil
+}
+
+// String returns the digest in lowercase hex.
+func (d Digest) String() string {
+	return hexify(d)
+}
+
+// MarshalBinary encodes the audit hash in the envelope of the package, its payload being the
+// direction byte (0x00 left, 0x01 right) followed by the hash.
+// This errors with ErrMalformedProof when the hash is empty or longer than 255 bytes.
+func (a AuditHash) MarshalBinary() ([]byte, error) {
+	if len(a.Val) == 0 || len(a.Val) > maxHashSize {
+		return nil, fmt.Errorf("%w: unsupported hash size %v", ErrMalformedProof, len(a.Val))
+	}
+	data := make([]byte, envelopeSize+1, envelopeSize+1+len(a.Val))
+	header := envelopeHeader(kindAuditHash)
+	copy(data, header[:])
+	if a.RightOperator {
+		data[envelopeSize] = 0x01
+	}
+	return append(data, a.Val...), nil
+}
+
+// UnmarshalBinary decodes an audit hash encoded by MarshalBinary.
+// This errors with ErrUnknownFormat or ErrUnknownFormatVersion when the data is not an audit hash
+// of this format version and with ErrMalformedProof on unknown direction bytes and hashes that
+// are empty or longer than 255 bytes.
+func (a *AuditHash) UnmarshalBinary(data []byte) error {
+	data, err := unseal(kindAuditHash, data)
+	if err != nil {
+		return err
+	}
+	if len(data) < 2 || len(data) > 1+maxHashSize {
+		return fmt.Errorf("%w: audit hash of %v bytes", ErrMalformedProof, len(data))
+	}
+	switch data[0] {
+	case 0x00:
+		a.RightOperator = false
+	case 0x01:
+		a.RightOperator = true
+	default:
+		return fmt.Errorf("%w: direction %#x", ErrMalformedProof, data[0])
+	}
+	a.Val = append([]byte(nil), data[1:]...)
+	return nil
+}
+
+// MarshalBinary encodes the inclusion proof as Hasher.MarshalInclusionProof does, marked with
+// HashCustom since a proof does not know its hash function.
+func (p InclusionProof) MarshalBinary() ([]byte, error) {
+	size := 0
+	if len(p.Path) > 0 {
+		size = len(p.Path[0].Val)
+	}
+	return marshalInclusion(HashCustom, size, p)
+}
+
+// UnmarshalBinary decodes an inclusion proof encoded by MarshalBinary or by
+// Hasher.MarshalInclusionProof for any hash function, within DefaultLimits.
+// This errors like Hasher.UnmarshalInclusionProof.
+func (p *InclusionProof) UnmarshalBinary(data []byte) error {
+	v, err := defaultHasher.unmarshalInclusion(data, true)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// MarshalInclusionProof encodes an inclusion proof in the envelope of the package with the following payload:
+//
+//	uint64  leaf index, big endian
+//	uint64  tree size, big endian
+//	the audit path in the payload layout of MarshalProof, marked with the HashID of the hash function
+//
+// This errors with ErrMalformedProof when the index or size are negative and like MarshalProof.
+func (h *Hasher) MarshalInclusionProof(p InclusionProof) ([]byte, error) {
+	return marshalInclusion(h.id, h.Size(), p)
+}
+
+func marshalInclusion(id HashID, size int, p InclusionProof) ([]byte, error) {
+	if p.LeafIndex < 0 || p.TreeSize < 0 {
+		return nil, fmt.Errorf("%w: index %v, tree size %v", ErrMalformedProof, p.LeafIndex, p.TreeSize)
+	}
+	data := make([]byte, envelopeSize+inclusionHeaderSize, envelopeSize+inclusionHeaderSize+proofHeaderSize+len(p.Path)*(1+size))
+	header := envelopeHeader(kindInclusionProof)
+	copy(data, header[:])
+	binary.BigEndian.PutUint64(data[envelopeSize:], uint64(p.LeafIndex))
+	binary.BigEndian.PutUint64(data[envelopeSize+8:], uint64(p.TreeSize))
+	return appendPath(data, id, size, p.Path)
+}
+
+// UnmarshalInclusionProof decodes an inclusion proof encoded by MarshalInclusionProof with the
+// Hasher's hash function, checking that its path has the length implied by its index and size.
+// This errors with ErrUnknownFormat or ErrUnknownFormatVersion when the data is not an inclusion
+// proof of this format version, with ErrMalformedProof when the data is truncated or the index,
+// size and path do not fit, with ErrTreeTooLarge when the size exceeds the Hasher's Limits and like UnmarshalProof.
+func (h *Hasher) UnmarshalInclusionProof(data []byte) (InclusionProof, error) {
+	return h.unmarshalInclusion(data, false)
+}
+
+func (h *Hasher) unmarshalInclusion(data []byte, anyHash bool) (InclusionProof, error) {
+	data, err := unseal(kindInclusionProof, data)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	if len(data) < inclusionHeaderSize {
+		return InclusionProof{}, fmt.Errorf("%w: truncated header", ErrMalformedProof)
+	}
+	index := binary.BigEndian.Uint64(data)
+	treeSize := binary.BigEndian.Uint64(data[8:])
+	if index >= treeSize || treeSize > uint64(maxInt) {
+		return InclusionProof{}, fmt.Errorf("%w: index %v, tree size %v", ErrMalformedProof, index, treeSize)
+	}
+	if err := h.checkTreeSize(int(treeSize)); err != nil {
+		return InclusionProof{}, err
+	}
+	path, err := h.parsePath(data[inclusionHeaderSize:], anyHash)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	p := InclusionProof{LeafIndex: int(index), TreeSize: int(treeSize), Path: path}
+	if want := ProofLen(p.LeafIndex, p.TreeSize); len(path) != want {
+		return InclusionProof{}, fmt.Errorf("%w: %v entries for index %v of %v, expected %v", ErrMalformedProof, len(path), index, treeSize, want)
+	}
+	return p, nil
+}
+
+// MarshalBinary encodes the tree as Save does, marked with the HashID of its hash function.
+func (t *Tree) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	if err := t.Save(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a tree encoded by MarshalBinary or Save, rebuilding its nodes from the
+// leaf hashes and checking them against the saved root as LoadTree does, after which the tree
+// serves proofs. A tree returned by NewTree decodes with its Hasher, a zero Tree, as made by
+// decoders such as encoding/gob, with the default prefixes of the hash function of the data.
+// This errors like LoadTree, with ErrInvalidSnapshot when the data has trailing bytes and with
+// ErrHashMismatch when its hash function is HashCustom for a zero Tree. Legacy snapshots are only
+// read by LoadTree.
+func (t *Tree) UnmarshalBinary(data []byte) error {
+	if err := checkEnvelope(kindSnapshot, data); err != nil {
+		return err
+	}
+	if len(data) < snapshotHeaderSize {
+		return fmt.Errorf("%w: truncated header", ErrInvalidSnapshot)
+	}
+	size, count := uint64(data[envelopeSize+1]), binary.BigEndian.Uint64(data[envelopeSize+2:])
+	if count > uint64(len(data)) || snapshotHeaderSize+(count+1)*size+sha256.Size != uint64(len(data)) {
+		return fmt.Errorf("%w: %v leaf hashes of size %v do not match %v bytes", ErrInvalidSnapshot, count, size, len(data))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h := t.h
+	if h == nil {
+		var err error
+		if h, err = hasherFor(HashID(data[envelopeSize])); err != nil {
+			return err
+		}
+	}
+	v, err := h.LoadTree(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	t.h, t.levels, t.leaves, t.versions = v.h, v.levels, v.leaves, nil
+	return nil
+}
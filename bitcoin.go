@@ -0,0 +1,166 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// Bitcoin merkle trees differ from the trees of this package: the transaction ids are the
+// leaves as they are, interior nodes are SHA256(SHA256(left || right)) without domain separation
+// prefixes and a level with an odd number of nodes duplicates its last node instead of carrying
+// it up. The transaction ids are expected in the internal byte order, the reverse of the order
+// block explorers display them in.
+//
+// Duplicating the last node means that a list ending with a repeated transaction id has the same
+// root as the list without the repetition (CVE-2012-2459), so a root alone does not prove the
+// number of transactions.
+
+// BitcoinRoot returns the merkle root of a block with the given transaction ids,
+// a block without transactions has no root and nil is returned.
+func BitcoinRoot(txids [][]byte) []byte {
+	levels := bitcoinLevels(txids)
+	if len(levels) == 0 {
+		return nil
+	}
+	return levels[len(levels)-1][0]
+}
+
+// BitcoinProof returns the audit path of the transaction at index i.
+// A transaction paired with its own duplicate has itself as the right hand side sibling.
+// This errors with ErrEmptyTree when there are no transactions and ErrIndexOutOfBounds
+// when the requested index is out of bounds.
+func BitcoinProof(txids [][]byte, i int) ([]AuditHash, error) {
+	if len(txids) == 0 {
+		return nil, ErrEmptyTree
+	}
+	if i < 0 || i >= len(txids) {
+		return nil, indexError(i, len(txids))
+	}
+
+	levels := bitcoinLevels(txids)
+	res := []AuditHash{}
+	for _, level := range levels[:len(levels)-1] {
+		sibling := i ^ 1
+		if sibling >= len(level) {
+			sibling = i
+		}
+		res = append(res, AuditHash{level[sibling], sibling >= i})
+		i /= 2
+	}
+	return res, nil
+}
+
+// VerifyBitcoinProof verifies that txid is included in the block whose merkle root is root.
+func VerifyBitcoinProof(root []byte, txid []byte, path []AuditHash) bool {
+	if len(root) != sha256.Size || len(txid) != sha256.Size {
+		return false
+	}
+
+	d := txid
+	for _, p := range path {
+		if len(p.Val) != sha256.Size {
+			return false
+		}
+		if p.RightOperator {
+			d = doubleSHA256(d, p.Val)
+		} else {
+			d = doubleSHA256(p.Val, d)
+		}
+	}
+	return equalDigest(root, d)
+}
+
+// bitcoinLevels returns every level of the bitcoin merkle tree over txids, leaves first.
+func bitcoinLevels(txids [][]byte) [][][]byte {
+	if len(txids) == 0 {
+		return nil
+	}
+
+	level := txids
+	levels := [][][]byte{level}
+	for len(level) > 1 {
+		next := make([][]byte, (len(level)+1)/2)
+		for i := range next {
+			right := level[2*i]
+			if 2*i+1 < len(level) {
+				right = level[2*i+1]
+			}
+			next[i] = doubleSHA256(level[2*i], right)
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return levels
+}
+
+// doubleSHA256 returns SHA256(SHA256(left || right)).
+func doubleSHA256(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write(left)
+	h.Write(right)
+	first := h.Sum(nil)
+	second := sha256.Sum256(first)
+	return second[:]
+}
+
+// BitcoinHeaderRoot returns the merkle root of a block from its transaction ids in the hex form
+// block explorers display them in, with the bytes of each id reversed. The root is returned both
+// in the internal byte order of the block header and in display order as hex.
+// This errors with ErrEmptyTree when there are no transactions and ErrInvalidHash when a
+// transaction id is not the hex of 32 bytes.
+func BitcoinHeaderRoot(txidsHex []string) (root []byte, rootHex string, err error) {
+	txids, err := parseBitcoinTxids(txidsHex)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(txids) == 0 {
+		return nil, "", ErrEmptyTree
+	}
+	root = BitcoinRoot(txids)
+	return root, hexify(reverseBytes(root)), nil
+}
+
+// BitcoinTxProof returns the audit path of the transaction at index i, from transaction ids in display order.
+// This errors like BitcoinHeaderRoot and BitcoinProof.
+func BitcoinTxProof(txidsHex []string, i int) ([]AuditHash, error) {
+	txids, err := parseBitcoinTxids(txidsHex)
+	if err != nil {
+		return nil, err
+	}
+	return BitcoinProof(txids, i)
+}
+
+// VerifyBitcoinTx verifies that the transaction whose id is txidHex, in display order, is included
+// in the block whose merkle root is rootHex, in display order, as an SPV client does.
+func VerifyBitcoinTx(rootHex, txidHex string, path []AuditHash) bool {
+	ids, err := parseBitcoinTxids([]string{rootHex, txidHex})
+	if err != nil {
+		return false
+	}
+	return VerifyBitcoinProof(ids[0], ids[1], path)
+}
+
+// parseBitcoinTxids decodes hashes in display order into the internal byte order.
+func parseBitcoinTxids(hexes []string) ([][]byte, error) {
+	ids := make([][]byte, len(hexes))
+	for i, s := range hexes {
+		id, err := unhexify(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: transaction %v: %v", ErrInvalidHash, i, err)
+		}
+		if len(id) != sha256.Size {
+			return nil, fmt.Errorf("%w: transaction %v has %v bytes, expected %v", ErrInvalidHash, i, len(id), sha256.Size)
+		}
+		ids[i] = reverseBytes(id)
+	}
+	return ids, nil
+}
+
+// reverseBytes returns a reversed copy of b.
+func reverseBytes(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i, c := range b {
+		r[len(b)-1-i] = c
+	}
+	return r
+}
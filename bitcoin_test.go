@@ -0,0 +1,51 @@
+package merkle
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestBitcoinBlock100000 checks the merkle root and the SPV proofs of the transactions of block
+// 100000 of the Bitcoin mainnet, with the fields of bitcoind's getblock in display order.
+func TestBitcoinBlock100000(t *testing.T) {
+	data, err := os.ReadFile("testdata/bitcoin_block_100000.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var block struct {
+		MerkleRoot string   `json:"merkleroot"`
+		Tx         []string `json:"tx"`
+	}
+	if err := json.Unmarshal(data, &block); err != nil {
+		t.Fatal(err)
+	}
+	_, rootHex, err := BitcoinHeaderRoot(block.Tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rootHex != block.MerkleRoot {
+		t.Fatalf("merkle root = %v, want %v", rootHex, block.MerkleRoot)
+	}
+	for i, txid := range block.Tx {
+		path, err := BitcoinTxProof(block.Tx, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyBitcoinTx(block.MerkleRoot, txid, path) {
+			t.Errorf("transaction %v does not verify", i)
+		}
+		if VerifyBitcoinTx(block.MerkleRoot, block.Tx[(i+1)%len(block.Tx)], path) {
+			t.Errorf("the proof of transaction %v verifies another transaction", i)
+		}
+	}
+}
+
+// TestBitcoinGenesisBlock checks that the root of a block of a single transaction is its id.
+func TestBitcoinGenesisBlock(t *testing.T) {
+	const coinbase = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	_, rootHex, err := BitcoinHeaderRoot([]string{coinbase})
+	if err != nil || rootHex != coinbase {
+		t.Fatalf("merkle root = %v, %v, want %v", rootHex, err, coinbase)
+	}
+}
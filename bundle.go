@@ -0,0 +1,289 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// Bundle is a self contained inclusion proof: the leaf, either as data or as its leaf hash,
+// its index, the size of the tree and the hash function, so that it verifies with the root alone.
+// Exactly one of Leaf and LeafHash is set.
+type Bundle struct {
+	Hash      HashID
+	Leaf      []byte
+	LeafHash  []byte
+	LeafIndex int
+	TreeSize  int
+	Path      []AuditHash
+}
+
+// CreateBundle returns the bundle of the item at index i.
+// This errors like Proof.
+func CreateBundle(items [][]byte, i int) (Bundle, error) {
+	return defaultHasher.CreateBundle(items, i)
+}
+
+// CreateBundle returns the bundle of the item at index i using the Hasher's hash function.
+func (h *Hasher) CreateBundle(items [][]byte, i int) (Bundle, error) {
+	p, err := h.Prove(items, i)
+	if err != nil {
+		return Bundle{}, err
+	}
+	return Bundle{
+		Hash:      h.id,
+		Leaf:      append([]byte{}, items[i]...),
+		LeafIndex: p.LeafIndex,
+		TreeSize:  p.TreeSize,
+		Path:      p.Path,
+	}, nil
+}
+
+// Verify verifies the bundle against root with the hash function it names, which must be one of
+// the hash functions known to the package, with the default prefixes.
+// The bundle is checked for consistency before root is looked at.
+// This errors with ErrHashMismatch for HashCustom, ErrMalformedProof when both or none of Leaf
+// and LeafHash are set, and otherwise like InclusionProof.Verify.
+func (b Bundle) Verify(root []byte) error {
+	h, err := hasherFor(b.Hash)
+	if err != nil {
+		return err
+	}
+	return h.VerifyBundle(root, b)
+}
+
+// VerifyBundle verifies a bundle using the Hasher's hash function, which must be the one it names.
+func (h *Hasher) VerifyBundle(root []byte, b Bundle) error {
+	if b.Hash != h.id {
+		return fmt.Errorf("%w: bundle uses hash %v, expected %v", ErrHashMismatch, b.Hash, h.id)
+	}
+	if (b.Leaf == nil) == (b.LeafHash == nil) {
+		return fmt.Errorf("%w: bundle must hold exactly one of the leaf and its hash", ErrMalformedProof)
+	}
+	if b.LeafHash != nil && len(b.LeafHash) != h.Size() {
+		return fmt.Errorf("%w: leaf hash has size %v, expected %v", ErrBadHashSize, len(b.LeafHash), h.Size())
+	}
+	p := InclusionProof{LeafIndex: b.LeafIndex, TreeSize: b.TreeSize, Path: b.Path}
+	if err := h.checkInclusion(root, p); err != nil {
+		return err
+	}
+	if err := h.checkLeafSize(len(b.Leaf)); err != nil {
+		return err
+	}
+
+	var ok bool
+	if b.LeafHash != nil {
+		ok = h.VerifyLeafHash(root, b.LeafHash, b.LeafIndex, b.Path)
+	} else {
+		ok = h.VerifyProof(root, b.Leaf, b.LeafIndex, b.Path)
+	}
+	if !ok {
+		return ErrRootMismatch
+	}
+	return nil
+}
+
+// bundleHeaderSize is the size of the fixed fields of an encoded bundle.
+const bundleHeaderSize = 1 + 1 + 8 + 8 + 4 + 1 + 4
+
+// Bundle leaf kinds in the binary encoding.
+const (
+	bundleLeafData = 0x00
+	bundleLeafHash = 0x01
+)
+
+// MarshalBinary encodes the bundle in the envelope of the package with the following payload:
+//
+//	uint8   HashID of the hash function
+//	uint8   0x00 when the leaf is its data, 0x01 when it is its leaf hash
+//	uint64  leaf index, big endian
+//	uint64  tree size, big endian
+//	uint32  length of the leaf, big endian
+//	uint8   size of each hash of the path in bytes
+//	uint32  number of entries of the path, big endian
+//	leaf
+//	entries each made of a direction byte (0x00 left, 0x01 right) followed by the hash
+//
+// This errors with ErrMalformedProof when the bundle does not hold exactly one of the leaf and
+// its hash, has a negative index or size or path hashes of different sizes.
+func (b Bundle) MarshalBinary() ([]byte, error) {
+	kind, leaf := byte(bundleLeafData), b.Leaf
+	if b.LeafHash != nil {
+		kind, leaf = bundleLeafHash, b.LeafHash
+	}
+	if (b.Leaf == nil) == (b.LeafHash == nil) {
+		return nil, fmt.Errorf("%w: bundle must hold exactly one of the leaf and its hash", ErrMalformedProof)
+	}
+	if b.LeafIndex < 0 || b.TreeSize < 0 || uint64(len(leaf)) > uint64(^uint32(0)) || uint64(len(b.Path)) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: bundle fields out of range", ErrMalformedProof)
+	}
+	size := 0
+	if len(b.Path) > 0 {
+		size = len(b.Path[0].Val)
+	}
+	if size > maxHashSize {
+		return nil, fmt.Errorf("%w: unsupported hash size %v", ErrMalformedProof, size)
+	}
+
+	data := make([]byte, bundleHeaderSize, bundleHeaderSize+len(leaf)+len(b.Path)*(1+size))
+	data[0] = byte(b.Hash)
+	data[1] = kind
+	binary.BigEndian.PutUint64(data[2:], uint64(b.LeafIndex))
+	binary.BigEndian.PutUint64(data[10:], uint64(b.TreeSize))
+	binary.BigEndian.PutUint32(data[18:], uint32(len(leaf)))
+	data[22] = byte(size)
+	binary.BigEndian.PutUint32(data[23:], uint32(len(b.Path)))
+	data = append(data, leaf...)
+	for i, entry := range b.Path {
+		if len(entry.Val) != size {
+			return nil, fmt.Errorf("%w: entry %v has size %v, expected %v", ErrMalformedProof, i, len(entry.Val), size)
+		}
+		direction := byte(0x00)
+		if entry.RightOperator {
+			direction = 0x01
+		}
+		data = append(data, direction)
+		data = append(data, entry.Val...)
+	}
+	return seal(kindBundle, data), nil
+}
+
+// UnmarshalBinary decodes a bundle encoded by MarshalBinary.
+// This errors with ErrUnknownFormat or ErrUnknownFormatVersion when the data is not a bundle of
+// this format version, with ErrMalformedProof when the data is truncated, has trailing bytes,
+// declares more bytes than it holds or contains an unknown leaf kind or direction byte, and with
+// ErrTreeTooLarge, ErrLeafTooLarge or ErrPathTooLong when it exceeds DefaultLimits.
+func (b *Bundle) UnmarshalBinary(data []byte) error {
+	v, err := defaultHasher.unmarshalBundle(data)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// UnmarshalBundle decodes a bundle encoded by MarshalBinary for the Hasher's hash function, within
+// the Hasher's Limits rather than DefaultLimits.
+// This errors with ErrHashMismatch when the bundle names another hash function and otherwise like
+// Bundle.UnmarshalBinary.
+func (h *Hasher) UnmarshalBundle(data []byte) (Bundle, error) {
+	b, err := h.unmarshalBundle(data)
+	if err != nil {
+		return Bundle{}, err
+	}
+	if b.Hash != h.id {
+		return Bundle{}, fmt.Errorf("%w: bundle uses hash %v, expected %v", ErrHashMismatch, b.Hash, h.id)
+	}
+	return b, nil
+}
+
+// unmarshalBundle decodes a bundle of any hash function within the Hasher's Limits.
+func (h *Hasher) unmarshalBundle(data []byte) (Bundle, error) {
+	data, err := unseal(kindBundle, data)
+	if err != nil {
+		return Bundle{}, err
+	}
+	if len(data) < bundleHeaderSize {
+		return Bundle{}, fmt.Errorf("%w: truncated header", ErrMalformedProof)
+	}
+	kind := data[1]
+	if kind != bundleLeafData && kind != bundleLeafHash {
+		return Bundle{}, fmt.Errorf("%w: unknown leaf kind %#x", ErrMalformedProof, kind)
+	}
+	index := binary.BigEndian.Uint64(data[2:])
+	treeSize := binary.BigEndian.Uint64(data[10:])
+	if index > uint64(maxInt) || treeSize > uint64(maxInt) {
+		return Bundle{}, fmt.Errorf("%w: index or tree size overflows", ErrMalformedProof)
+	}
+	leafLen := uint64(binary.BigEndian.Uint32(data[18:]))
+	size := uint64(data[22])
+	count := uint64(binary.BigEndian.Uint32(data[23:]))
+	if err := h.checkTreeSize(int(treeSize)); err != nil {
+		return Bundle{}, err
+	}
+	if err := h.checkLeafSize(int(leafLen)); err != nil {
+		return Bundle{}, err
+	}
+	if err := h.checkPathLen(int(count)); err != nil {
+		return Bundle{}, err
+	}
+	body := data[bundleHeaderSize:]
+	if leafLen+count*(1+size) != uint64(len(body)) {
+		return Bundle{}, fmt.Errorf("%w: leaf of %v bytes and %v entries of size %v do not match %v bytes", ErrMalformedProof, leafLen, count, size, len(body))
+	}
+
+	v := Bundle{Hash: HashID(data[0]), LeafIndex: int(index), TreeSize: int(treeSize), Path: make([]AuditHash, count)}
+	leaf := append([]byte{}, body[:leafLen]...)
+	if kind == bundleLeafHash {
+		v.LeafHash = leaf
+	} else {
+		v.Leaf = leaf
+	}
+	body = body[leafLen:]
+	for i := range v.Path {
+		entry := body[:1+size]
+		body = body[1+size:]
+		switch entry[0] {
+		case 0x00:
+		case 0x01:
+			v.Path[i].RightOperator = true
+		default:
+			return Bundle{}, fmt.Errorf("%w: entry %v has direction %#x", ErrMalformedProof, i, entry[0])
+		}
+		v.Path[i].Val = append([]byte(nil), entry[1:]...)
+	}
+	return v, nil
+}
+
+// bundleJSON is the JSON representation of a Bundle.
+type bundleJSON struct {
+	Hash      HashID      `json:"hash"`
+	Leaf      *string     `json:"leaf,omitempty"`
+	LeafHash  *string     `json:"leaf_hash,omitempty"`
+	LeafIndex int         `json:"leaf_index"`
+	TreeSize  int         `json:"tree_size"`
+	Path      []AuditHash `json:"path"`
+}
+
+// MarshalJSON encodes the bundle as an object with the hash identifier, the leaf or leaf hash
+// in lowercase hex, the leaf index, the tree size and the path encoded as AuditHash.MarshalJSON does.
+func (b Bundle) MarshalJSON() ([]byte, error) {
+	v := bundleJSON{Hash: b.Hash, LeafIndex: b.LeafIndex, TreeSize: b.TreeSize, Path: b.Path}
+	if v.Path == nil {
+		v.Path = []AuditHash{}
+	}
+	if b.Leaf != nil {
+		s := hexify(b.Leaf)
+		v.Leaf = &s
+	}
+	if b.LeafHash != nil {
+		s := hexify(b.LeafHash)
+		v.LeafHash = &s
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a bundle encoded by MarshalJSON.
+// This errors with ErrMalformedProof when the leaf or the path cannot be decoded.
+func (b *Bundle) UnmarshalJSON(data []byte) error {
+	var v bundleJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	res := Bundle{Hash: v.Hash, LeafIndex: v.LeafIndex, TreeSize: v.TreeSize, Path: v.Path}
+	for _, f := range []struct {
+		s   *string
+		dst *[]byte
+	}{{v.Leaf, &res.Leaf}, {v.LeafHash, &res.LeafHash}} {
+		if f.s == nil {
+			continue
+		}
+		val, err := unhexify(*f.s)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformedProof, err)
+		}
+		*f.dst = val
+	}
+	*b = res
+	return nil
+}
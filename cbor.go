@@ -0,0 +1,240 @@
+package merkle
+
+import (
+	"fmt"
+	"math"
+)
+
+// CBOR major types used by the proof encoding.
+const (
+	cborUint   = 0
+	cborBytes  = 2
+	cborArray  = 4
+	cborMap    = 5
+	cborSimple = 7
+
+	cborFalse = 20
+	cborTrue  = 21
+)
+
+// Keys of the CBOR map of an inclusion proof.
+const (
+	cborKeyHashID = iota
+	cborKeyLeafIndex
+	cborKeyTreeSize
+	cborKeyPath
+	cborKeys
+)
+
+// MarshalProofCBOR encodes an inclusion proof produced with the default hash function
+// as described by Hasher.MarshalProofCBOR.
+func MarshalProofCBOR(p InclusionProof) ([]byte, error) {
+	return defaultHasher.MarshalProofCBOR(p)
+}
+
+// MarshalProofCBOR encodes an inclusion proof as deterministic CBOR (RFC 8949 section 4.2.1),
+// a map with small integer keys:
+//
+//	0  HashID of the hash function
+//	1  leaf index
+//	2  tree size
+//	3  audit path, an array of [right, hash] pairs, right a boolean and hash a byte string
+//
+// Every item has a definite length and every integer its shortest encoding, so a proof
+// has exactly one encoding. Every hash of the path must have the size of the Hasher's digests.
+func (h *Hasher) MarshalProofCBOR(p InclusionProof) ([]byte, error) {
+	if p.LeafIndex < 0 || p.TreeSize < 0 {
+		return nil, fmt.Errorf("%w: index %v, tree size %v", ErrMalformedProof, p.LeafIndex, p.TreeSize)
+	}
+	size := h.Size()
+	data := make([]byte, 0, 16+len(p.Path)*(size+4))
+	data = cborHead(data, cborMap, cborKeys)
+	data = cborHead(data, cborUint, cborKeyHashID)
+	data = cborHead(data, cborUint, uint64(h.id))
+	data = cborHead(data, cborUint, cborKeyLeafIndex)
+	data = cborHead(data, cborUint, uint64(p.LeafIndex))
+	data = cborHead(data, cborUint, cborKeyTreeSize)
+	data = cborHead(data, cborUint, uint64(p.TreeSize))
+	data = cborHead(data, cborUint, cborKeyPath)
+	data = cborHead(data, cborArray, uint64(len(p.Path)))
+	for i, entry := range p.Path {
+		if len(entry.Val) != size {
+			return nil, fmt.Errorf("%w: entry %v has size %v, expected %v", ErrMalformedProof, i, len(entry.Val), size)
+		}
+		data = cborHead(data, cborArray, 2)
+		if entry.RightOperator {
+			data = cborHead(data, cborSimple, cborTrue)
+		} else {
+			data = cborHead(data, cborSimple, cborFalse)
+		}
+		data = cborHead(data, cborBytes, uint64(size))
+		data = append(data, entry.Val...)
+	}
+	return data, nil
+}
+
+// UnmarshalProofCBOR decodes an inclusion proof encoded by MarshalProofCBOR for the default hash function.
+func UnmarshalProofCBOR(data []byte) (InclusionProof, error) {
+	return defaultHasher.UnmarshalProofCBOR(data)
+}
+
+// UnmarshalProofCBOR decodes an inclusion proof encoded by MarshalProofCBOR.
+// This errors with ErrHashMismatch when the proof was encoded for another hash function and
+// with ErrMalformedProof when the data is not the deterministic encoding of a proof: indefinite
+// lengths, integers not in their shortest form, missing, unknown or unordered keys, hashes
+// that do not have the size of the Hasher's digests or trailing bytes, and with ErrTreeTooLarge or
+// ErrPathTooLong when the proof exceeds the Hasher's Limits.
+func (h *Hasher) UnmarshalProofCBOR(data []byte) (InclusionProof, error) {
+	r := &cborReader{data: data}
+	if err := r.expect(cborMap, cborKeys); err != nil {
+		return InclusionProof{}, err
+	}
+
+	var fields [cborKeyPath]uint64
+	for key := range fields {
+		if err := r.expect(cborUint, uint64(key)); err != nil {
+			return InclusionProof{}, err
+		}
+		v, err := r.uint()
+		if err != nil {
+			return InclusionProof{}, err
+		}
+		fields[key] = v
+	}
+	if id := fields[cborKeyHashID]; id != uint64(h.id) {
+		return InclusionProof{}, fmt.Errorf("%w: proof uses hash %v, expected %v", ErrHashMismatch, id, h.id)
+	}
+	if fields[cborKeyLeafIndex] > uint64(maxInt) || fields[cborKeyTreeSize] > uint64(maxInt) {
+		return InclusionProof{}, fmt.Errorf("%w: index or tree size overflows", ErrMalformedProof)
+	}
+	if err := h.checkTreeSize(int(fields[cborKeyTreeSize])); err != nil {
+		return InclusionProof{}, err
+	}
+
+	if err := r.expect(cborUint, cborKeyPath); err != nil {
+		return InclusionProof{}, err
+	}
+	count, err := r.head(cborArray)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	if count > uint64(maxInt) {
+		count = uint64(maxInt)
+	}
+	if err := h.checkPathLen(int(count)); err != nil {
+		return InclusionProof{}, err
+	}
+	size := uint64(h.Size())
+	// Each entry takes more bytes than its hash, check the count before allocating anything.
+	if count > uint64(len(r.data)-r.off)/(size+1) {
+		return InclusionProof{}, fmt.Errorf("%w: %v entries do not fit in %v bytes", ErrMalformedProof, count, len(r.data)-r.off)
+	}
+	path := make([]AuditHash, count)
+	for i := range path {
+		if err := r.expect(cborArray, 2); err != nil {
+			return InclusionProof{}, err
+		}
+		right, err := r.head(cborSimple)
+		if err != nil {
+			return InclusionProof{}, err
+		}
+		switch right {
+		case cborFalse:
+		case cborTrue:
+			path[i].RightOperator = true
+		default:
+			return InclusionProof{}, fmt.Errorf("%w: entry %v has direction %v", ErrMalformedProof, i, right)
+		}
+		n, err := r.head(cborBytes)
+		if err != nil {
+			return InclusionProof{}, err
+		}
+		if n != size || uint64(len(r.data)-r.off) < n {
+			return InclusionProof{}, fmt.Errorf("%w: entry %v has size %v, expected %v", ErrMalformedProof, i, n, size)
+		}
+		path[i].Val = append([]byte(nil), r.data[r.off:r.off+int(n)]...)
+		r.off += int(n)
+	}
+	if r.off != len(r.data) {
+		return InclusionProof{}, fmt.Errorf("%w: %v trailing bytes", ErrMalformedProof, len(r.data)-r.off)
+	}
+
+	return InclusionProof{
+		LeafIndex: int(fields[cborKeyLeafIndex]),
+		TreeSize:  int(fields[cborKeyTreeSize]),
+		Path:      path,
+	}, nil
+}
+
+// cborHead appends the shortest encoding of the head of an item of the given major type.
+func cborHead(data []byte, major byte, v uint64) []byte {
+	m := major << 5
+	switch {
+	case v < 24:
+		return append(data, m|byte(v))
+	case v <= math.MaxUint8:
+		return append(data, m|24, byte(v))
+	case v <= math.MaxUint16:
+		return append(data, m|25, byte(v>>8), byte(v))
+	case v <= math.MaxUint32:
+		return append(data, m|26, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+	return append(data, m|27, byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// cborReader decodes the deterministic CBOR written by cborHead.
+type cborReader struct {
+	data []byte
+	off  int
+}
+
+// head reads the head of an item of the given major type and returns its argument.
+func (r *cborReader) head(major byte) (uint64, error) {
+	if r.off >= len(r.data) {
+		return 0, fmt.Errorf("%w: truncated CBOR", ErrMalformedProof)
+	}
+	b := r.data[r.off]
+	r.off++
+	if b>>5 != major {
+		return 0, fmt.Errorf("%w: CBOR major type %v, expected %v", ErrMalformedProof, b>>5, major)
+	}
+
+	info := b & 0x1f
+	if info < 24 {
+		return uint64(info), nil
+	}
+	if info > 27 {
+		// 28 to 30 are reserved and 31 marks indefinite lengths.
+		return 0, fmt.Errorf("%w: CBOR additional information %v", ErrMalformedProof, info)
+	}
+	n := 1 << (info - 24)
+	if len(r.data)-r.off < n {
+		return 0, fmt.Errorf("%w: truncated CBOR", ErrMalformedProof)
+	}
+	var v uint64
+	for _, c := range r.data[r.off : r.off+n] {
+		v = v<<8 | uint64(c)
+	}
+	r.off += n
+	if len(cborHead(nil, major, v)) != 1+n || (major == cborSimple && v < 32) {
+		return 0, fmt.Errorf("%w: CBOR argument %v not in its shortest form", ErrMalformedProof, v)
+	}
+	return v, nil
+}
+
+// uint reads an unsigned integer.
+func (r *cborReader) uint() (uint64, error) {
+	return r.head(cborUint)
+}
+
+// expect reads the head of an item and checks its argument.
+func (r *cborReader) expect(major byte, v uint64) error {
+	got, err := r.head(major)
+	if err != nil {
+		return err
+	}
+	if got != v {
+		return fmt.Errorf("%w: CBOR argument %v, expected %v", ErrMalformedProof, got, v)
+	}
+	return nil
+}
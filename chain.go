@@ -0,0 +1,175 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// ProofChain proves that a leaf is included in a tree whose root is a leaf of a tree whose root is
+// a leaf of another tree, and so on up to a top tree. Its segments are ordered from the tree
+// holding the leaf up to the top tree, the root reached by each segment being the leaf of the next.
+type ProofChain []ChainSegment
+
+// ChainSegment is the inclusion proof of one link of a ProofChain.
+// HashLeaf tells whether the leaf of the segment is an item, hashed with the leaf prefix as by
+// Root and AggregateRoots, or already a leaf hash, as for RootFromLeafHashes.
+type ChainSegment struct {
+	InclusionProof
+	HashLeaf bool
+}
+
+// chainSegmentHeaderSize is the size of the fields of a segment before its path.
+const chainSegmentHeaderSize = 1 + inclusionHeaderSize
+
+// VerifyChain verifies that leaf is included through every segment of chain in the top tree whose
+// root is topRoot.
+// This errors with ErrMalformedProof for an empty chain and otherwise names the segment at fault,
+// wrapping ErrLeafTooLarge or ErrBadHashSize for its leaf, the errors of VerifyInclusion for its
+// structure, and ErrRootMismatch, reported on the last segment, when the chain does not lead to
+// topRoot.
+func VerifyChain(topRoot, leaf []byte, chain ProofChain) error {
+	return defaultHasher.VerifyChain(topRoot, leaf, chain)
+}
+
+// VerifyChain verifies a proof chain using the Hasher's hash function for every segment.
+func (h *Hasher) VerifyChain(topRoot, leaf []byte, chain ProofChain) error {
+	if len(chain) == 0 {
+		return fmt.Errorf("%w: empty proof chain", ErrMalformedProof)
+	}
+	if len(topRoot) != h.Size() {
+		return fmt.Errorf("%w: root has size %v, expected %v", ErrBadHashSize, len(topRoot), h.Size())
+	}
+	node := leaf
+	for k, seg := range chain {
+		if err := h.checkInclusionShape(seg.InclusionProof); err != nil {
+			return fmt.Errorf("merkle: chain segment %v: %w", k, err)
+		}
+		var d []byte
+		if seg.HashLeaf {
+			if err := h.checkLeafSize(len(node)); err != nil {
+				return fmt.Errorf("merkle: chain segment %v: %w", k, err)
+			}
+			d = h.leafHashAt(make([]byte, 0, h.Size()), seg.LeafIndex, node)
+		} else {
+			if len(node) != h.Size() {
+				return fmt.Errorf("merkle: chain segment %v: %w: leaf hash has size %v, expected %v", k, ErrBadHashSize, len(node), h.Size())
+			}
+			d = append(make([]byte, 0, h.Size()), node...)
+		}
+		node = h.foldPath(d, seg.Path)
+	}
+	if equalDigest(topRoot, h.empty) {
+		return fmt.Errorf("merkle: chain segment %v: %w: the empty tree includes no leaf", len(chain)-1, ErrRootMismatch)
+	}
+	if err := h.matchRoot(topRoot, node); err != nil {
+		return fmt.Errorf("merkle: chain segment %v: %w", len(chain)-1, err)
+	}
+	return nil
+}
+
+// MarshalProofChain encodes a proof chain of the default hash function, see Hasher.MarshalProofChain.
+func MarshalProofChain(chain ProofChain) ([]byte, error) {
+	return defaultHasher.MarshalProofChain(chain)
+}
+
+// MarshalProofChain encodes a proof chain of the Hasher's hash function as
+//
+//	uint32 number of segments
+//	for each segment, from the leaf up:
+//	    uint8  1 when the leaf of the segment is hashed with the leaf prefix, 0 otherwise
+//	    uint64 leaf index
+//	    uint64 tree size
+//	    the audit path as encoded by MarshalProof, without its envelope
+//
+// This errors with ErrMalformedProof when a segment has a negative index or size or an entry
+// that does not have the digest size.
+func (h *Hasher) MarshalProofChain(chain ProofChain) ([]byte, error) {
+	if uint64(len(chain)) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: chain has %v segments", ErrMalformedProof, len(chain))
+	}
+	header := envelopeHeader(kindProofChain)
+	data := append(header[:], 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[envelopeSize:], uint32(len(chain)))
+	for k, seg := range chain {
+		if seg.LeafIndex < 0 || seg.TreeSize < 0 {
+			return nil, fmt.Errorf("%w: segment %v has index %v, tree size %v", ErrMalformedProof, k, seg.LeafIndex, seg.TreeSize)
+		}
+		var fields [chainSegmentHeaderSize]byte
+		if seg.HashLeaf {
+			fields[0] = 1
+		}
+		binary.BigEndian.PutUint64(fields[1:], uint64(seg.LeafIndex))
+		binary.BigEndian.PutUint64(fields[9:], uint64(seg.TreeSize))
+		data = append(data, fields[:]...)
+		var err error
+		if data, err = appendPath(data, h.id, h.Size(), seg.Path); err != nil {
+			return nil, fmt.Errorf("merkle: chain segment %v: %w", k, err)
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalProofChain decodes a proof chain encoded by MarshalProofChain for the default hash function.
+func UnmarshalProofChain(data []byte) (ProofChain, error) {
+	return defaultHasher.UnmarshalProofChain(data)
+}
+
+// UnmarshalProofChain decodes a proof chain encoded by MarshalProofChain with the Hasher's hash function.
+// This errors with ErrUnknownFormat or ErrUnknownFormatVersion when the data is not a proof chain of
+// this format version, with ErrMalformedProof when it is truncated or a segment does not fit, naming
+// the segment, with ErrTreeTooLarge when a size exceeds the Hasher's Limits and like UnmarshalProof.
+func (h *Hasher) UnmarshalProofChain(data []byte) (ProofChain, error) {
+	data, err := unseal(kindProofChain, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("%w: truncated header", ErrMalformedProof)
+	}
+	count := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	// Every segment takes at least its fields and an empty path, bound the count before allocating.
+	if uint64(count)*(chainSegmentHeaderSize+proofHeaderSize) > uint64(len(data)) {
+		return nil, fmt.Errorf("%w: %v segments do not fit in %v bytes", ErrMalformedProof, count, len(data))
+	}
+	chain := make(ProofChain, count)
+	for k := range chain {
+		seg := &chain[k]
+		if len(data) < chainSegmentHeaderSize+proofHeaderSize {
+			return nil, fmt.Errorf("%w: segment %v is truncated", ErrMalformedProof, k)
+		}
+		switch data[0] {
+		case 0:
+		case 1:
+			seg.HashLeaf = true
+		default:
+			return nil, fmt.Errorf("%w: segment %v has flags %#x", ErrMalformedProof, k, data[0])
+		}
+		index := binary.BigEndian.Uint64(data[1:])
+		treeSize := binary.BigEndian.Uint64(data[9:])
+		if index >= treeSize || treeSize > uint64(maxInt) {
+			return nil, fmt.Errorf("%w: segment %v has index %v, tree size %v", ErrMalformedProof, k, index, treeSize)
+		}
+		if err := h.checkTreeSize(int(treeSize)); err != nil {
+			return nil, fmt.Errorf("merkle: chain segment %v: %w", k, err)
+		}
+		seg.LeafIndex, seg.TreeSize = int(index), int(treeSize)
+		data = data[chainSegmentHeaderSize:]
+
+		n := proofHeaderSize + uint64(binary.BigEndian.Uint32(data[1:]))*(1+uint64(data[5]))
+		if n > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: the path of segment %v is truncated", ErrMalformedProof, k)
+		}
+		if seg.Path, err = h.parsePath(data[:n], false); err != nil {
+			return nil, fmt.Errorf("merkle: chain segment %v: %w", k, err)
+		}
+		if want := ProofLen(seg.LeafIndex, seg.TreeSize); len(seg.Path) != want {
+			return nil, fmt.Errorf("%w: segment %v has %v entries for index %v of %v, expected %v", ErrMalformedProof, k, len(seg.Path), index, treeSize, want)
+		}
+		data = data[n:]
+	}
+	if len(data) != 0 {
+		return nil, fmt.Errorf("%w: %v trailing bytes", ErrMalformedProof, len(data))
+	}
+	return chain, nil
+}
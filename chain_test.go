@@ -0,0 +1,103 @@
+package merkle
+
+import (
+	"errors"
+	"testing"
+)
+
+// newTestChain returns a leaf and its chain through three trees: the leaf is an item of a tree of
+// 5 items, whose root is an item of a tree of 6 items, whose root is a leaf hash of a top tree of 3
+// leaf hashes.
+func newTestChain(t *testing.T) ([]byte, []byte, ProofChain) {
+	bottom := testItems(5)
+	leaf := bottom[3]
+	bottomProof, err := Prove(bottom, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	middle := testItems(6)
+	middle[4] = Root(bottom)
+	middleProof, err := Prove(middle, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	top := [][]byte{LeafHash([]byte("x")), Root(middle), LeafHash([]byte("y"))}
+	topPath, err := ProofFromLeafHashes(top, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	topRoot, err := RootFromLeafHashes(top)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return topRoot, leaf, ProofChain{
+		{InclusionProof: bottomProof, HashLeaf: true},
+		{InclusionProof: middleProof, HashLeaf: true},
+		{InclusionProof: InclusionProof{LeafIndex: 1, TreeSize: 3, Path: topPath}, HashLeaf: false},
+	}
+}
+
+func TestVerifyChain(t *testing.T) {
+	topRoot, leaf, chain := newTestChain(t)
+	if err := VerifyChain(topRoot, leaf, chain); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyChain(topRoot, []byte("item 2"), chain); !errors.Is(err, ErrRootMismatch) {
+		t.Errorf("another leaf: got %v, want ErrRootMismatch", err)
+	}
+	if err := VerifyChain(topRoot, leaf, nil); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("empty chain: got %v, want ErrMalformedProof", err)
+	}
+
+	// The leaf of the top segment is the root of the middle tree as it is, not hashed again.
+	flipped := append(ProofChain(nil), chain...)
+	flipped[2].HashLeaf = true
+	if err := VerifyChain(topRoot, leaf, flipped); !errors.Is(err, ErrRootMismatch) {
+		t.Errorf("top leaf hashed again: got %v, want ErrRootMismatch", err)
+	}
+
+	// Dropping a level makes the chain reach the top tree from the wrong root.
+	if err := VerifyChain(topRoot, leaf, ProofChain{chain[0], chain[2]}); !errors.Is(err, ErrRootMismatch) {
+		t.Errorf("without the middle segment: got %v, want ErrRootMismatch", err)
+	}
+}
+
+func TestProofChainBinary(t *testing.T) {
+	topRoot, leaf, chain := newTestChain(t)
+	data, err := MarshalProofChain(chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalProofChain(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyChain(topRoot, leaf, got); err != nil {
+		t.Fatalf("decoded chain: %v", err)
+	}
+	for k := range chain {
+		if got[k].HashLeaf != chain[k].HashLeaf || got[k].LeafIndex != chain[k].LeafIndex ||
+			got[k].TreeSize != chain[k].TreeSize || len(got[k].Path) != len(chain[k].Path) {
+			t.Fatalf("segment %v: got %+v, want %+v", k, got[k], chain[k])
+		}
+		for j := range chain[k].Path {
+			if !equalDigest(got[k].Path[j].Val, chain[k].Path[j].Val) || got[k].Path[j].RightOperator != chain[k].Path[j].RightOperator {
+				t.Fatalf("segment %v entry %v: got %v, want %v", k, j, got[k].Path[j], chain[k].Path[j])
+			}
+		}
+	}
+
+	for n := 0; n < len(data); n++ {
+		if _, err := UnmarshalProofChain(data[:n]); err == nil {
+			t.Fatalf("truncated to %v bytes: no error", n)
+		}
+	}
+	if _, err := UnmarshalProofChain(append(data, 0)); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("trailing byte: got %v, want ErrMalformedProof", err)
+	}
+	if _, err := NewHasher(WithSHA256()).UnmarshalProofChain(data); err == nil {
+		t.Error("chain of another hash function: no error")
+	}
+}
@@ -0,0 +1,98 @@
+package merkle
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// ChunkWriter is an io.WriteCloser that splits the stream written to it into chunks of a fixed
+// size, the last one possibly shorter, and hashes each chunk as a leaf as soon as it is complete.
+// Its root is the Root of the chunks. An empty stream has no chunk and the root of the empty tree.
+type ChunkWriter struct {
+	size     int
+	buf      []byte
+	frontier *Frontier
+	tree     *Tree
+	closed   bool
+}
+
+// NewChunkWriter returns a ChunkWriter over chunks of chunkSize bytes that keeps O(log n) hashes,
+// so it computes the root but no proofs. The options configure the hash function as for NewHasher.
+// This errors with ErrInvalidChunkSize when chunkSize is not positive.
+func NewChunkWriter(chunkSize int, opts ...Option) (*ChunkWriter, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChunkSize, chunkSize)
+	}
+	return &ChunkWriter{size: chunkSize, buf: make([]byte, 0, chunkSize), frontier: NewFrontier(opts...)}, nil
+}
+
+// NewChunkTreeWriter returns a ChunkWriter over chunks of chunkSize bytes that keeps the hash of
+// every chunk in a Tree, from which proofs of the chunks can be read.
+// This errors with ErrInvalidChunkSize when chunkSize is not positive.
+func NewChunkTreeWriter(chunkSize int, opts ...Option) (*ChunkWriter, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChunkSize, chunkSize)
+	}
+	return &ChunkWriter{size: chunkSize, buf: make([]byte, 0, chunkSize), tree: NewTree(nil, opts...)}, nil
+}
+
+// Write hashes every chunk p completes and keeps the rest for the next call.
+// Writing after Close errors with fs.ErrClosed.
+func (w *ChunkWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fs.ErrClosed
+	}
+	n := len(p)
+	for len(p) > 0 {
+		take := w.size - len(w.buf)
+		if take > len(p) {
+			take = len(p)
+		}
+		w.buf = append(w.buf, p[:take]...)
+		p = p[take:]
+		if len(w.buf) == w.size {
+			w.flush()
+		}
+	}
+	return n, nil
+}
+
+// Close hashes the last chunk if it is short. Closing more than once has no effect.
+func (w *ChunkWriter) Close() error {
+	if !w.closed && len(w.buf) > 0 {
+		w.flush()
+	}
+	w.closed = true
+	return nil
+}
+
+func (w *ChunkWriter) flush() {
+	if w.tree != nil {
+		w.tree.Append(w.buf)
+	} else {
+		w.frontier.Append(w.buf)
+	}
+	w.buf = w.buf[:0]
+}
+
+// Root returns the root hash of the tree over the chunks, which includes a short last chunk only after Close.
+func (w *ChunkWriter) Root() []byte {
+	if w.tree != nil {
+		return w.tree.Root()
+	}
+	return w.frontier.Root()
+}
+
+// LeafCount returns the number of chunks hashed, which includes a short last chunk only after Close.
+func (w *ChunkWriter) LeafCount() int {
+	if w.tree != nil {
+		return w.tree.LeafCount()
+	}
+	return w.frontier.Size()
+}
+
+// Tree returns the tree over the chunks of a ChunkWriter created by NewChunkTreeWriter, and nil
+// for one created by NewChunkWriter. The tree grows as chunks are written.
+func (w *ChunkWriter) Tree() *Tree {
+	return w.tree
+}
@@ -0,0 +1,208 @@
+// Command merkle computes merkle roots and inclusion proofs and verifies proofs.
+//
+//	merkle root   [--hash name] [--input file | --files file...]
+//	merkle prove  [--hash name] [--input file | --files file...] --index N
+//	merkle verify [--hash name] --root hex --leaf hex --proof proof.json
+//
+// The items are the lines of the input file, or of the standard input when neither --input nor
+// --files is given, or the contents of the files given with --files. root prints the root in hex,
+// prove prints the inclusion proof of the item at index N as JSON, which verify reads back with
+// the root and the hex encoded item. The hash is one of sha3-256, the default, sha256, keccak256
+// and blake3. verify exits with status 1 when the proof does not verify, usage errors exit with 2.
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	merkle "github.com/actuallyachraf/go-merkle"
+)
+
+// hashes maps the names accepted by --hash to their options.
+var hashes = map[string][]merkle.Option{
+	"sha3-256":  nil,
+	"sha256":    {merkle.WithSHA256()},
+	"keccak256": {merkle.WithKeccak256()},
+	"blake3":    {merkle.WithBLAKE3()},
+}
+
+// errUsage marks the errors of the command line rather than of the inputs.
+var errUsage = errors.New("merkle: usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with args, without the program name, and returns its exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	err := dispatch(args, stdin, stdout, stderr)
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintln(stderr, err)
+	if errors.Is(err, errUsage) {
+		return 2
+	}
+	return 1
+}
+
+func dispatch(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: merkle root|prove|verify [flags]", errUsage)
+	}
+	fs := flag.NewFlagSet("merkle "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	hash := fs.String("hash", "sha3-256", "hash function: sha3-256, sha256, keccak256 or blake3")
+
+	switch args[0] {
+	case "root":
+		input, files := itemFlags(fs)
+		if err := parse(fs, args[1:]); err != nil {
+			return err
+		}
+		h, err := hasher(*hash)
+		if err != nil {
+			return err
+		}
+		items, err := readItems(*input, *files, fs.Args(), stdin)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(stdout, "%x\n", h.Root(items))
+		return err
+
+	case "prove":
+		input, files := itemFlags(fs)
+		index := fs.Int("index", -1, "index of the item to prove")
+		if err := parse(fs, args[1:]); err != nil {
+			return err
+		}
+		h, err := hasher(*hash)
+		if err != nil {
+			return err
+		}
+		items, err := readItems(*input, *files, fs.Args(), stdin)
+		if err != nil {
+			return err
+		}
+		p, err := h.Prove(items, *index)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(stdout, "%s\n", data)
+		return err
+
+	case "verify":
+		rootHex := fs.String("root", "", "hex encoded root")
+		leafHex := fs.String("leaf", "", "hex encoded item")
+		proofFile := fs.String("proof", "", "JSON proof written by prove")
+		if err := parse(fs, args[1:]); err != nil {
+			return err
+		}
+		if *rootHex == "" || *proofFile == "" || fs.NArg() != 0 {
+			return fmt.Errorf("%w: verify needs --root, --leaf and --proof", errUsage)
+		}
+		h, err := hasher(*hash)
+		if err != nil {
+			return err
+		}
+		root, err := h.ParseRootHex(*rootHex)
+		if err != nil {
+			return err
+		}
+		leaf, err := hex.DecodeString(*leafHex)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(*proofFile)
+		if err != nil {
+			return err
+		}
+		var p merkle.InclusionProof
+		if err := json.Unmarshal(data, &p); err != nil {
+			return fmt.Errorf("%w: %v", merkle.ErrMalformedProof, err)
+		}
+		if err := h.VerifyInclusion(root, leaf, p); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(stdout, "ok")
+		return err
+	}
+	return fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+}
+
+func itemFlags(fs *flag.FlagSet) (*string, *bool) {
+	input := fs.String("input", "", "file with one item per line, the standard input by default")
+	files := fs.Bool("files", false, "use the contents of the files given as arguments as items")
+	return input, files
+}
+
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nil
+}
+
+func hasher(name string) (*merkle.Hasher, error) {
+	opts, ok := hashes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown hash %q", errUsage, name)
+	}
+	return merkle.NewHasher(opts...), nil
+}
+
+// readItems returns the contents of the files when files is set and otherwise the lines of the
+// input file, or of stdin when input is empty.
+func readItems(input string, files bool, args []string, stdin io.Reader) ([][]byte, error) {
+	switch {
+	case files && input != "":
+		return nil, fmt.Errorf("%w: --input and --files are exclusive", errUsage)
+	case files:
+		items := make([][]byte, len(args))
+		for i, name := range args {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = data
+		}
+		return items, nil
+	case len(args) != 0:
+		return nil, fmt.Errorf("%w: unexpected arguments %q", errUsage, args)
+	}
+
+	var data []byte
+	var err error
+	if input == "" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lines(data), nil
+}
+
+// lines splits data into lines, dropping the line ending, \n or \r\n, and a final empty line.
+func lines(data []byte) [][]byte {
+	if len(data) == 0 {
+		return [][]byte{}
+	}
+	items := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	for i, item := range items {
+		items[i] = bytes.TrimSuffix(item, []byte("\r"))
+	}
+	return items
+}
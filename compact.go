@@ -0,0 +1,167 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// CompactProof is an inclusion proof without directions: in the positional tree the side of
+// every entry follows from the leaf index and the tree size, so only the hashes are kept.
+// This makes the proof smaller and leaves no direction bit for a peer to flip.
+type CompactProof struct {
+	LeafIndex int
+	TreeSize  int
+	Hashes    [][]byte
+}
+
+// pathDirections returns the RightOperator of each entry of the audit path of index i in a tree of n items.
+func pathDirections(i, n int) []bool {
+	directions := []bool{}
+	walkPath(i, n, func(level, node, sibling int) {
+		directions = append(directions, sibling > node)
+	})
+	return directions
+}
+
+// Compact drops the directions of an inclusion proof.
+// This errors with ErrIndexOutOfBounds or ErrBadPathLength when the proof does not fit a tree
+// of its size, and with ErrMalformedProof when a direction differs from the one of the tree.
+func (p InclusionProof) Compact() (CompactProof, error) {
+	if p.LeafIndex < 0 || p.LeafIndex >= p.TreeSize {
+		return CompactProof{}, indexError(p.LeafIndex, p.TreeSize)
+	}
+	directions := pathDirections(p.LeafIndex, p.TreeSize)
+	if len(directions) != len(p.Path) {
+		return CompactProof{}, fmt.Errorf("%w: %v entries, expected %v", ErrBadPathLength, len(p.Path), len(directions))
+	}
+
+	hashes := make([][]byte, len(p.Path))
+	for j, entry := range p.Path {
+		if entry.RightOperator != directions[j] {
+			return CompactProof{}, fmt.Errorf("%w: entry %v is on the wrong side", ErrMalformedProof, j)
+		}
+		hashes[j] = entry.Val
+	}
+	return CompactProof{LeafIndex: p.LeafIndex, TreeSize: p.TreeSize, Hashes: hashes}, nil
+}
+
+// Expand restores the directions of a compact proof.
+// This errors with ErrIndexOutOfBounds or ErrBadPathLength when the proof does not fit a tree of its size.
+func (c CompactProof) Expand() (InclusionProof, error) {
+	if c.LeafIndex < 0 || c.LeafIndex >= c.TreeSize {
+		return InclusionProof{}, indexError(c.LeafIndex, c.TreeSize)
+	}
+	directions := pathDirections(c.LeafIndex, c.TreeSize)
+	if len(directions) != len(c.Hashes) {
+		return InclusionProof{}, fmt.Errorf("%w: %v entries, expected %v", ErrBadPathLength, len(c.Hashes), len(directions))
+	}
+
+	path := make([]AuditHash, len(c.Hashes))
+	for j, val := range c.Hashes {
+		path[j] = AuditHash{val, directions[j]}
+	}
+	return InclusionProof{LeafIndex: c.LeafIndex, TreeSize: c.TreeSize, Path: path}, nil
+}
+
+// Verify verifies that leaf is included in the tree whose root is root.
+// This errors like InclusionProof.Verify.
+func (c CompactProof) Verify(root, leaf []byte) error {
+	return defaultHasher.VerifyCompact(root, leaf, c)
+}
+
+// VerifyCompact verifies a compact proof using the Hasher's hash function.
+func (h *Hasher) VerifyCompact(root, leaf []byte, c CompactProof) error {
+	p, err := c.Expand()
+	if err != nil {
+		return err
+	}
+	return h.VerifyInclusion(root, leaf, p)
+}
+
+// compactHeaderSize is the size of the hash identifier, leaf index, tree size and digest size of an encoded compact proof.
+const compactHeaderSize = 1 + 8 + 8 + 1
+
+// MarshalCompactProof encodes a compact proof for the default hash function,
+// see Hasher.MarshalCompactProof.
+func MarshalCompactProof(c CompactProof) ([]byte, error) {
+	return defaultHasher.MarshalCompactProof(c)
+}
+
+// MarshalCompactProof encodes a compact proof in the envelope of the package with the following payload:
+//
+//	uint8   HashID of the hash function
+//	uint64  leaf index, big endian
+//	uint64  tree size, big endian
+//	uint8   size of each hash in bytes
+//	hashes, as many as ProofLen(leaf index, tree size)
+func (h *Hasher) MarshalCompactProof(c CompactProof) ([]byte, error) {
+	if _, err := c.Expand(); err != nil {
+		return nil, err
+	}
+	size := h.Size()
+	if size == 0 || size > maxHashSize {
+		return nil, fmt.Errorf("%w: unsupported hash size %v", ErrMalformedProof, size)
+	}
+
+	data := make([]byte, compactHeaderSize, compactHeaderSize+len(c.Hashes)*size)
+	data[0] = byte(h.id)
+	binary.BigEndian.PutUint64(data[1:], uint64(c.LeafIndex))
+	binary.BigEndian.PutUint64(data[9:], uint64(c.TreeSize))
+	data[17] = byte(size)
+	for j, val := range c.Hashes {
+		if len(val) != size {
+			return nil, fmt.Errorf("%w: entry %v has size %v, expected %v", ErrMalformedProof, j, len(val), size)
+		}
+		data = append(data, val...)
+	}
+	return seal(kindCompactProof, data), nil
+}
+
+// UnmarshalCompactProof decodes a compact proof encoded by MarshalCompactProof for the default hash function.
+func UnmarshalCompactProof(data []byte) (CompactProof, error) {
+	return defaultHasher.UnmarshalCompactProof(data)
+}
+
+// UnmarshalCompactProof decodes a compact proof encoded by MarshalCompactProof.
+// This errors with ErrUnknownFormat or ErrUnknownFormatVersion when the data is not a compact proof
+// of this format version, with ErrHashMismatch when the proof was encoded for another hash function,
+// with ErrMalformedProof when the data does not hold exactly the hashes its index and size imply,
+// and with ErrTreeTooLarge when the tree size exceeds the Hasher's Limits.
+func (h *Hasher) UnmarshalCompactProof(data []byte) (CompactProof, error) {
+	data, err := unseal(kindCompactProof, data)
+	if err != nil {
+		return CompactProof{}, err
+	}
+	if len(data) < compactHeaderSize {
+		return CompactProof{}, fmt.Errorf("%w: truncated header", ErrMalformedProof)
+	}
+	if id := HashID(data[0]); id != h.id {
+		return CompactProof{}, fmt.Errorf("%w: proof uses hash %v, expected %v", ErrHashMismatch, id, h.id)
+	}
+	index := binary.BigEndian.Uint64(data[1:])
+	treeSize := binary.BigEndian.Uint64(data[9:])
+	size := int(data[17])
+	if size != h.Size() {
+		return CompactProof{}, fmt.Errorf("%w: hash size %v, expected %v", ErrMalformedProof, size, h.Size())
+	}
+	if treeSize > uint64(maxInt) || index >= treeSize {
+		return CompactProof{}, fmt.Errorf("%w: index %v, tree size %v", ErrMalformedProof, index, treeSize)
+	}
+	if err := h.checkTreeSize(int(treeSize)); err != nil {
+		return CompactProof{}, err
+	}
+
+	count := ProofLen(int(index), int(treeSize))
+	body := data[compactHeaderSize:]
+	if len(body) != count*size {
+		return CompactProof{}, fmt.Errorf("%w: %v bytes for %v hashes of size %v", ErrMalformedProof, len(body), count, size)
+	}
+	hashes := make([][]byte, count)
+	for j := range hashes {
+		hashes[j] = append([]byte(nil), body[j*size:(j+1)*size]...)
+	}
+	return CompactProof{LeafIndex: int(index), TreeSize: int(treeSize), Hashes: hashes}, nil
+}
+
+// maxInt is the largest value of an int.
+const maxInt = int(^uint(0) >> 1)
@@ -0,0 +1,112 @@
+package merkle
+
+import "fmt"
+
+// ConsistencyProof returns the proof that the tree over items[:oldSize] is a prefix
+// of the tree over items, following the RFC 6962 subproof algorithm.
+// The RightOperator of each entry tells on which side of the path it was taken.
+// This errors with ErrIndexOutOfBounds when oldSize is negative or larger than the number of items.
+func ConsistencyProof(items [][]byte, oldSize int) ([]AuditHash, error) {
+	return defaultHasher.ConsistencyProof(items, oldSize)
+}
+
+// ConsistencyProof returns the RFC 6962 consistency proof between the trees over items[:oldSize]
+// and items using the Hasher's hash function.
+// This errors with ErrIndexOutOfBounds when oldSize is negative or larger than the number of items.
+func (h *Hasher) ConsistencyProof(items [][]byte, oldSize int) ([]AuditHash, error) {
+	if oldSize < 0 || oldSize > len(items) {
+		return nil, fmt.Errorf("%w: old size %v, tree has %v items", ErrIndexOutOfBounds, oldSize, len(items))
+	}
+	// The empty tree is a prefix of every tree and a tree is a prefix of itself.
+	if oldSize == 0 || oldSize == len(items) {
+		return []AuditHash{}, nil
+	}
+	return h.subproof(oldSize, items, 0, true), nil
+}
+
+// subproof implements SUBPROOF(m, D[n], b) from RFC 6962 section 2.1.2 for the subtree over
+// items whose first leaf is at offset.
+func (h *Hasher) subproof(m int, items [][]byte, offset int, complete bool) []AuditHash {
+	if m == len(items) {
+		if complete {
+			return []AuditHash{}
+		}
+		return []AuditHash{{h.rootAt(offset, items), false}}
+	}
+
+	k := prevPowerOfTwo(len(items))
+	if m <= k {
+		res := h.subproof(m, items[:k], offset, complete)
+		return append(res, AuditHash{h.rootAt(offset+k, items[k:]), true})
+	}
+	res := h.subproof(m-k, items[k:], offset+k, false)
+	return append(res, AuditHash{h.rootAt(offset, items[:k]), false})
+}
+
+// VerifyConsistency verifies that the tree of size oldSize with root oldRoot is a prefix
+// of the tree of size newSize with root newRoot.
+func VerifyConsistency(oldRoot, newRoot []byte, oldSize, newSize int, proof []AuditHash) bool {
+	return defaultHasher.VerifyConsistency(oldRoot, newRoot, oldSize, newSize, proof)
+}
+
+// VerifyConsistency verifies an RFC 6962 consistency proof using the Hasher's hash function,
+// as described in RFC 9162 section 2.1.4.2.
+func (h *Hasher) VerifyConsistency(oldRoot, newRoot []byte, oldSize, newSize int, proof []AuditHash) bool {
+	size := h.Size()
+	if len(oldRoot) != size || len(newRoot) != size {
+		return false
+	}
+	for _, p := range proof {
+		if len(p.Val) != size {
+			return false
+		}
+	}
+
+	switch {
+	case oldSize < 0 || oldSize > newSize:
+		return false
+	case oldSize == 0:
+		return len(proof) == 0 && equalDigest(oldRoot, h.emptyHash())
+	case oldSize == newSize:
+		return len(proof) == 0 && equalDigest(oldRoot, newRoot)
+	}
+
+	path := make([][]byte, 0, len(proof)+1)
+	// When the old tree is a complete subtree its root is the first node of the path.
+	if oldSize&(oldSize-1) == 0 {
+		path = append(path, oldRoot)
+	}
+	for _, p := range proof {
+		path = append(path, p.Val)
+	}
+	if len(path) == 0 {
+		return false
+	}
+
+	fn, sn := oldSize-1, newSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+
+	fr, sr := path[0], path[0]
+	for _, c := range path[1:] {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = h.NodeHash(c, fr)
+			sr = h.NodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = h.NodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+
+	return sn == 0 && equalDigest(fr, oldRoot) && equalDigest(sr, newRoot)
+}
@@ -0,0 +1,84 @@
+package merkle
+
+import (
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// The default customization strings of WithCSHAKE256.
+const (
+	CSHAKELeafCustomization = "merkle-leaf"
+	CSHAKENodeCustomization = "merkle-node"
+)
+
+// cshakeSize is the size of the digests of the cSHAKE256 mode.
+const cshakeSize = 32
+
+// WithCSHAKE256 hashes leaves and interior nodes with cSHAKE256 (NIST SP 800-185) with 32 byte
+// outputs, separating them by the customization strings CSHAKELeafCustomization and
+// CSHAKENodeCustomization, with an empty function name, instead of prefix bytes:
+//
+//	leaf         cSHAKE256(data, 256, "", "merkle-leaf")
+//	node         cSHAKE256(left || right, 256, "", "merkle-node")
+//	empty tree   cSHAKE256("", 256, "", "merkle-node")
+//
+// The options changing the data of a leaf, such as WithLeafIndexBinding, apply as for the other
+// hash functions. Proofs serialized by the Hasher are marked with HashCSHAKE256.
+// The empty tree hashes to edd29df987e3ba972bee7b18960bcc45f03113a086d01e542bca2913c71dbb5b and
+// the leaf "abc" to a36687858ddd6333f6d05f6ac102419884314289b1f4684624c0626f87563a16,
+// the items "a", "b", "c" to e700a568e9c30d44d14c10d8d2c7f6e2e562ff12d58d11dc81e6e7232800a628.
+func WithCSHAKE256() Option {
+	return withCSHAKE256(HashCSHAKE256, CSHAKELeafCustomization, CSHAKENodeCustomization)
+}
+
+// WithCSHAKE256Customization hashes like WithCSHAKE256 with the given customization strings, which
+// must differ. Proofs serialized by the Hasher are marked with HashCustom.
+// NewHasher panics when the strings are equal.
+func WithCSHAKE256Customization(leaf, node string) Option {
+	return withCSHAKE256(HashCustom, leaf, node)
+}
+
+func withCSHAKE256(id HashID, leaf, node string) Option {
+	return func(h *Hasher) {
+		if leaf == node {
+			panic(fmt.Sprintf("merkle: the cSHAKE256 customization strings of leaves and nodes must differ, both are %q", leaf))
+		}
+		withNamedHash(id, newCSHAKE256(node))(h)
+		h.newLeafHash = newCSHAKE256(leaf)
+		// cSHAKE256 separates leaves from nodes by itself.
+		h.leafPrefix = nil
+		h.interiorPrefix = nil
+		h.unsafePrefixes = true
+	}
+}
+
+// newCSHAKE256 returns a constructor of cSHAKE256 states with the customization s.
+func newCSHAKE256(s string) func() hash.Hash {
+	return func() hash.Hash {
+		return &cshake{s: sha3.NewCShake256(nil, []byte(s))}
+	}
+}
+
+// cshake is a hash.Hash of cSHAKE256 with 32 byte outputs.
+// It keeps the data written since the last Reset and absorbs it into s on Sum, which resets s
+// rather than squeezing a Clone of it, since cloning allocates on every node.
+type cshake struct {
+	s    sha3.ShakeHash
+	data []byte
+	out  [cshakeSize]byte // read through the interface of s, it would escape on the stack
+}
+
+func (c *cshake) Write(p []byte) (int, error) { c.data = append(c.data, p...); return len(p), nil }
+func (c *cshake) Reset()                      { c.data = c.data[:0] }
+func (c *cshake) Size() int                   { return cshakeSize }
+func (c *cshake) BlockSize() int              { return 136 }
+
+// Sum appends the output to b, writes can go on as the data is kept.
+func (c *cshake) Sum(b []byte) []byte {
+	c.s.Reset()
+	c.s.Write(c.data)
+	c.s.Read(c.out[:])
+	return append(b, c.out[:]...)
+}
@@ -0,0 +1,47 @@
+package merkle
+
+import "testing"
+
+// TestCSHAKE256Sample checks the cSHAKE256 state against the first 32 bytes of Sample #3 of the
+// cSHAKE examples of NIST, data 00010203 with the customization "Email Signature".
+func TestCSHAKE256Sample(t *testing.T) {
+	d := newCSHAKE256("Email Signature")()
+	d.Write([]byte{0, 1, 2, 3})
+	const want = "d008828e2b80ac9d2218ffee1d070c48b8e4c87bff32c9699d5b6896eee0edd1"
+	if got := hexify(d.Sum(nil)); got != want {
+		t.Fatalf("cSHAKE256 = %v, want %v", got, want)
+	}
+}
+
+// The vectors of the cSHAKE256 mode were computed by an independent implementation of NIST
+// SP 800-185, itself checked against the NIST samples.
+func TestCSHAKE256Vectors(t *testing.T) {
+	items := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d"), []byte("e")}
+	h := NewHasher(WithCSHAKE256())
+	custom := NewHasher(WithCSHAKE256Customization("L", "N"))
+	for _, c := range []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{"empty tree", h.Root(nil), "edd29df987e3ba972bee7b18960bcc45f03113a086d01e542bca2913c71dbb5b"},
+		{"leaf abc", h.LeafHash([]byte("abc")), "a36687858ddd6333f6d05f6ac102419884314289b1f4684624c0626f87563a16"},
+		{"a, b, c", h.Root(items[:3]), "e700a568e9c30d44d14c10d8d2c7f6e2e562ff12d58d11dc81e6e7232800a628"},
+		{"a to e", h.Root(items), "6fc4e1621695400c32dd6269e935595f67950323b1ca93cab09387dd227b2032"},
+		{"customized empty tree", custom.Root(nil), "c2f69f72452c57ad9308ebd0a1538f9bac0074028a61781c28a10cdb3daf726f"},
+		{"customized a, b, c", custom.Root(items[:3]), "0f6346652402d9c907b343445514b5dc8347297ed16fe0d44c70ea975f69fe6d"},
+	} {
+		if hexify(c.got) != c.want {
+			t.Errorf("%v = %x, want %v", c.name, c.got, c.want)
+		}
+	}
+	for i := range items {
+		p, err := h.Prove(items, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := h.VerifyInclusion(h.Root(items), items[i], p); err != nil {
+			t.Errorf("proof of %v: %v", i, err)
+		}
+	}
+}
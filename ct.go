@@ -0,0 +1,54 @@
+package merkle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ctHasher hashes like the Merkle trees of Certificate Transparency logs, RFC 6962 with SHA-256.
+var ctHasher = NewHasher(WithSHA256())
+
+// ctProofResponse is the body of a /ct/v1/get-proof-by-hash response.
+type ctProofResponse struct {
+	LeafIndex *int64   `json:"leaf_index"`
+	AuditPath []string `json:"audit_path"`
+}
+
+// ParseCTProof parses the JSON response of the get-proof-by-hash endpoint of a Certificate
+// Transparency log, queried for a tree of treeSize entries, into an InclusionProof.
+// The audit nodes may be base64 encoded with or without padding.
+// This errors with ErrMalformedProof when the response cannot be decoded and with
+// ErrIndexOutOfBounds or ErrBadPathLength when the proof does not fit a tree of treeSize entries.
+func ParseCTProof(response []byte, treeSize int) (InclusionProof, error) {
+	var r ctProofResponse
+	if err := json.Unmarshal(response, &r); err != nil {
+		return InclusionProof{}, fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	if r.LeafIndex == nil || r.AuditPath == nil {
+		return InclusionProof{}, fmt.Errorf("%w: missing leaf_index or audit_path", ErrMalformedProof)
+	}
+
+	hashes := make([][]byte, len(r.AuditPath))
+	for j, node := range r.AuditPath {
+		h, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(node, "="))
+		if err != nil {
+			return InclusionProof{}, fmt.Errorf("%w: audit node %v: %v", ErrMalformedProof, j, err)
+		}
+		hashes[j] = h
+	}
+	return FromTrillianProof(hashes, *r.LeafIndex, int64(treeSize))
+}
+
+// VerifyCTProof verifies that leaf, the TLS encoding of a MerkleTreeLeaf, is included in the
+// tree of a Certificate Transparency log whose signed tree head has the given root and size,
+// using the JSON response of its get-proof-by-hash endpoint.
+// This errors like ParseCTProof and InclusionProof.Verify.
+func VerifyCTProof(root []byte, treeSize int, leaf, response []byte) error {
+	p, err := ParseCTProof(response, treeSize)
+	if err != nil {
+		return err
+	}
+	return ctHasher.VerifyInclusion(root, leaf, p)
+}
@@ -0,0 +1,46 @@
+package merkle
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+type ctFixture struct {
+	STH struct {
+		TreeSize int    `json:"tree_size"`
+		RootHash []byte `json:"sha256_root_hash"`
+	} `json:"sth"`
+	LeafInput []byte          `json:"leaf_input"`
+	LeafHash  []byte          `json:"leaf_hash"`
+	Response  json.RawMessage `json:"response"`
+}
+
+func TestVerifyCTProof(t *testing.T) {
+	data, err := os.ReadFile("testdata/ct_get_proof_by_hash.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f ctFixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	if got := NewHasher(WithSHA256()).LeafHash(f.LeafInput); !equalDigest(got, f.LeafHash) {
+		t.Fatalf("leaf hash = %x, want %x", got, f.LeafHash)
+	}
+	if err := VerifyCTProof(f.STH.RootHash, f.STH.TreeSize, f.LeafInput, f.Response); err != nil {
+		t.Errorf("VerifyCTProof: %v", err)
+	}
+	unpadded := []byte(strings.ReplaceAll(string(f.Response), "=", ""))
+	if err := VerifyCTProof(f.STH.RootHash, f.STH.TreeSize, f.LeafInput, unpadded); err != nil {
+		t.Errorf("VerifyCTProof without base64 padding: %v", err)
+	}
+	if err := VerifyCTProof(f.STH.RootHash, 6, f.LeafInput, f.Response); !errors.Is(err, ErrIndexOutOfBounds) {
+		t.Errorf("VerifyCTProof for a tree head not holding the entry: got %v, want ErrIndexOutOfBounds", err)
+	}
+	if err := VerifyCTProof(f.STH.RootHash, f.STH.TreeSize, append(f.LeafInput, 0), f.Response); !errors.Is(err, ErrRootMismatch) {
+		t.Errorf("VerifyCTProof of another leaf: got %v, want ErrRootMismatch", err)
+	}
+}
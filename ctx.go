@@ -0,0 +1,57 @@
+package merkle
+
+import "context"
+
+// ctxCheckInterval is the number of leaves or nodes hashed between two checks of a context,
+// small enough for a cancelled build to return within milliseconds and large enough for the
+// checks not to show in the cost of hashing.
+const ctxCheckInterval = 1 << 12
+
+// RootCtx is Root returning ctx.Err() and no root as soon as it sees ctx is done.
+// The context is checked before hashing and then every few thousand hashes.
+func RootCtx(ctx context.Context, items [][]byte) ([]byte, error) {
+	return defaultHasher.RootCtx(ctx, items)
+}
+
+// RootCtx is Root using the Hasher's hash function and returning ctx.Err() as soon as it sees ctx is done.
+func (h *Hasher) RootCtx(ctx context.Context, items [][]byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return h.root(ctx, 0, items)
+}
+
+// NewTreeCtx is NewTree returning ctx.Err() as soon as it sees ctx is done.
+// A cancelled build returns no tree, so a tree is either fully built or not returned at all.
+func NewTreeCtx(ctx context.Context, items [][]byte, opts ...Option) (*Tree, error) {
+	return NewHasher(opts...).NewTreeCtx(ctx, items)
+}
+
+// NewTreeCtx is NewTree using the Hasher's hash function and returning ctx.Err() as soon as it sees ctx is done.
+func (h *Hasher) NewTreeCtx(ctx context.Context, items [][]byte) (*Tree, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return h.newTree(ctx, items)
+}
+
+// ProofAllCtx is ProofAll returning ctx.Err() and no path as soon as it sees ctx is done.
+// This errors with ErrEmptyTree when there are no items.
+func ProofAllCtx(ctx context.Context, items [][]byte) ([][]AuditHash, error) {
+	return defaultHasher.ProofAllCtx(ctx, items)
+}
+
+// ProofAllCtx is ProofAll using the Hasher's hash function and returning ctx.Err() as soon as it sees ctx is done.
+func (h *Hasher) ProofAllCtx(ctx context.Context, items [][]byte) ([][]AuditHash, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyTree
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	t, err := h.newTree(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	return t.proofAll(ctx)
+}
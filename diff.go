@@ -0,0 +1,55 @@
+package merkle
+
+import "fmt"
+
+// Diff returns the indices at which the items of a and b differ, in increasing order: the
+// indices below min(len(a), len(b)) whose items differ, followed by every index from
+// min(len(a), len(b)) up to max(len(a), len(b)), the items appended or truncated.
+func Diff(a, b [][]byte) ([]int, error) {
+	return DiffTrees(NewTree(a), NewTree(b))
+}
+
+// DiffTrees returns the indices at which the leaves of two trees differ, as Diff does.
+// Only the subtrees whose roots differ are descended into, so trees that differ at c
+// leaves are compared in O(c log n) nodes.
+// This errors with ErrHashMismatch when the trees do not hash the same way.
+func DiffTrees(a, b *Tree) ([]int, error) {
+	defer rLockPair(a, b)()
+	if !a.h.sameHashing(b.h) {
+		return nil, fmt.Errorf("%w: comparing trees built with different hashers", ErrHashMismatch)
+	}
+
+	na, nb := a.leafCount(), b.leafCount()
+	common, total := na, nb
+	if nb < na {
+		common, total = nb, na
+	}
+
+	diff := []int{}
+	var walk func(level, index int)
+	walk = func(level, index int) {
+		lo, hi := index<<uint(level), (index+1)<<uint(level)
+		if lo >= common {
+			return
+		}
+		// A subtree within the common leaves is complete in both trees, compare it as a whole.
+		if hi <= common {
+			if equalDigest(a.levels[level][index], b.levels[level][index]) {
+				return
+			}
+			if level == 0 {
+				diff = append(diff, index)
+				return
+			}
+		}
+		walk(level-1, 2*index)
+		walk(level-1, 2*index+1)
+	}
+	if common > 0 {
+		walk(Depth(common), 0)
+	}
+	for i := common; i < total; i++ {
+		diff = append(diff, i)
+	}
+	return diff, nil
+}
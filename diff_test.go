@@ -0,0 +1,44 @@
+package merkle
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDiffSeams compares trees of every size up to 9 differing at every index, so that the
+// changed leaves and the ends of the shorter trees fall on both sides of the non power of two
+// splits.
+func TestDiffSeams(t *testing.T) {
+	items := testItems(9)
+	for na := 0; na <= 9; na++ {
+		for nb := 0; nb <= 9; nb++ {
+			common := minInt(na, nb)
+			for changed := -1; changed < common; changed++ {
+				b := append([][]byte(nil), items[:nb]...)
+				want := []int{}
+				if changed >= 0 {
+					b[changed] = []byte("changed")
+					want = append(want, changed)
+				}
+				for i := common; i < na || i < nb; i++ {
+					want = append(want, i)
+				}
+				got, err := Diff(items[:na], b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("%v and %v items changed at %v: got %v, want %v", na, nb, changed, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDiffTreesLockOrder(t *testing.T) {
+	testLockOrder(t, func(a, b *Tree) {
+		if _, err := DiffTrees(a, b); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package merkle
+
+import "fmt"
+
+// FindDuplicates returns the indices of every item that appears more than once, keyed by the
+// item, in increasing order. Items that appear once are not in the map.
+func FindDuplicates(items [][]byte) map[string][]int {
+	seen := make(map[string][]int, len(items))
+	for i, item := range items {
+		seen[string(item)] = append(seen[string(item)], i)
+	}
+	for item, indices := range seen {
+		if len(indices) < 2 {
+			delete(seen, item)
+		}
+	}
+	return seen
+}
+
+// Duplicates returns the indices of every leaf of the tree that appears more than once,
+// keyed by its leaf hash, in increasing order.
+// This errors with ErrHashMismatch when the tree binds the index of its leaves into their hashes,
+// which are then all distinct whatever the items, FindDuplicates finding the repeated items.
+func (t *Tree) Duplicates() (map[string][]int, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.h.indexedLeaves {
+		return nil, fmt.Errorf("%w: the leaf hashes of the tree bind their indices", ErrHashMismatch)
+	}
+	dups := make(map[string][]int)
+	for leaf, indices := range t.leafIndex() {
+		if len(indices) > 1 {
+			dups[leaf] = append([]int(nil), indices...)
+		}
+	}
+	return dups, nil
+}
+
+// NewUniqueTree is NewTree for items that must all be distinct.
+// This errors with ErrDuplicateLeaf naming the first item that repeats an earlier one.
+func NewUniqueTree(items [][]byte, opts ...Option) (*Tree, error) {
+	return NewHasher(opts...).NewUniqueTree(items)
+}
+
+// NewUniqueTree is NewTree for items that must all be distinct, using the Hasher's hash function.
+// The items are compared as bytes, so that they must be distinct with WithLeafIndexBinding too.
+func (h *Hasher) NewUniqueTree(items [][]byte) (*Tree, error) {
+	seen := make(map[string]int, len(items))
+	for i, item := range items {
+		if j, ok := seen[string(item)]; ok {
+			return nil, fmt.Errorf("%w: item %v repeats item %v", ErrDuplicateLeaf, i, j)
+		}
+		seen[string(item)] = i
+	}
+	return h.NewTree(items), nil
+}
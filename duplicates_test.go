@@ -0,0 +1,39 @@
+package merkle
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDuplicates(t *testing.T) {
+	items := [][]byte{[]byte("a"), []byte("b"), []byte("a"), []byte("c"), []byte("b"), []byte("a")}
+	want := map[string][]int{"a": {0, 2, 5}, "b": {1, 4}}
+	if got := FindDuplicates(items); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicates = %v, want %v", got, want)
+	}
+	got, err := NewTree(items).Duplicates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || !reflect.DeepEqual(got[string(LeafHash(items[0]))], want["a"]) || !reflect.DeepEqual(got[string(LeafHash(items[1]))], want["b"]) {
+		t.Errorf("Tree.Duplicates = %v, want %v by leaf hash", got, want)
+	}
+	if _, err := NewTree(items, WithLeafIndexBinding()).Duplicates(); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("Duplicates with index binding: got %v, want ErrHashMismatch", err)
+	}
+}
+
+func TestNewUniqueTree(t *testing.T) {
+	items := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("b"), []byte("a")}
+	for _, opts := range [][]Option{nil, {WithLeafIndexBinding()}} {
+		_, err := NewUniqueTree(items, opts...)
+		if !errors.Is(err, ErrDuplicateLeaf) || err.Error() != "merkle: duplicate leaf: item 3 repeats item 1" {
+			t.Errorf("NewUniqueTree: got %v", err)
+		}
+		tree, err := NewUniqueTree(items[:3], opts...)
+		if err != nil || !equalDigest(tree.Root(), NewHasher(opts...).Root(items[:3])) {
+			t.Errorf("NewUniqueTree of distinct items: %v", err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// proofHeaderSize is the size of the hash identifier, path length and digest size fields of an encoded proof.
+	proofHeaderSize = 6
+	// maxHashSize is the largest hash size the encodings accept.
+	maxHashSize = 255
+)
+
+// MarshalProof encodes an audit path produced with the default hash function
+// in the layout described by Hasher.MarshalProof.
+func MarshalProof(path []AuditHash) ([]byte, error) {
+	return defaultHasher.MarshalProof(path)
+}
+
+// MarshalProof encodes an audit path in the envelope of the package with the following payload:
+//
+//	uint8   HashID of the hash function
+//	uint32  number of entries, big endian
+//	uint8   size of each hash in bytes
+//	entries each made of a direction byte (0x00 left, 0x01 right) followed by the hash
+//
+// Every hash of the path must have the size of the Hasher's digests.
+func (h *Hasher) MarshalProof(path []AuditHash) ([]byte, error) {
+	header := envelopeHeader(kindProof)
+	return appendPath(header[:], h.id, h.Size(), path)
+}
+
+// appendPath appends the encoding of path described by Hasher.MarshalProof to data, marked with id.
+// The size may only be 0 for an empty path.
+func appendPath(data []byte, id HashID, size int, path []AuditHash) ([]byte, error) {
+	if size == 0 && len(path) > 0 || size > maxHashSize {
+		return nil, fmt.Errorf("%w: unsupported hash size %v", ErrMalformedProof, size)
+	}
+	if uint64(len(path)) > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: path has %v entries", ErrMalformedProof, len(path))
+	}
+
+	offset := len(data)
+	data = append(data, make([]byte, proofHeaderSize)...)
+	data[offset] = byte(id)
+	binary.BigEndian.PutUint32(data[offset+1:], uint32(len(path)))
+	data[offset+5] = byte(size)
+	for i, entry := range path {
+		if len(entry.Val) != size {
+			return nil, fmt.Errorf("%w: entry %v has size %v, expected %v", ErrMalformedProof, i, len(entry.Val), size)
+		}
+		direction := byte(0x00)
+		if entry.RightOperator {
+			direction = 0x01
+		}
+		data = append(data, direction)
+		data = append(data, entry.Val...)
+	}
+	return data, nil
+}
+
+// UnmarshalProof decodes an audit path encoded by MarshalProof for the default hash function.
+func UnmarshalProof(data []byte) ([]AuditHash, error) {
+	return defaultHasher.UnmarshalProof(data)
+}
+
+// UnmarshalProof decodes an audit path encoded by MarshalProof.
+// This errors with ErrUnknownFormat or ErrUnknownFormatVersion when the data is not an audit path
+// of this format version, with ErrHashMismatch when the proof was encoded for another hash function
+// and with ErrMalformedProof when the data is truncated, has trailing bytes, declares more entries
+// than it holds or contains an unknown direction byte. A path with more entries than the Hasher's
+// Limits allow errors with ErrPathTooLong before its entries are read.
+func (h *Hasher) UnmarshalProof(data []byte) ([]AuditHash, error) {
+	payload, err := unseal(kindProof, data)
+	if err != nil {
+		return nil, err
+	}
+	return h.parsePath(payload, false)
+}
+
+// parsePath decodes an audit path encoded by MarshalProof, for any hash function and hash size
+// when anyHash is set and otherwise for the Hasher's only, within the Hasher's Limits.
+func (h *Hasher) parsePath(data []byte, anyHash bool) ([]AuditHash, error) {
+	if len(data) < proofHeaderSize {
+		return nil, fmt.Errorf("%w: truncated header", ErrMalformedProof)
+	}
+	if id := HashID(data[0]); id != h.id && !anyHash {
+		return nil, fmt.Errorf("%w: proof uses hash %v, expected %v", ErrHashMismatch, id, h.id)
+	}
+	count := uint64(binary.BigEndian.Uint32(data[1:]))
+	if err := h.checkPathLen(int(count)); err != nil {
+		return nil, err
+	}
+	size := uint64(data[5])
+	switch {
+	case anyHash && size == 0 && count > 0:
+		return nil, fmt.Errorf("%w: hash size 0", ErrMalformedProof)
+	case !anyHash && size != uint64(h.Size()):
+		return nil, fmt.Errorf("%w: hash size %v, expected %v", ErrMalformedProof, size, h.Size())
+	}
+	// Check the declared length against the data before allocating anything.
+	body := data[proofHeaderSize:]
+	if count*(1+size) != uint64(len(body)) {
+		return nil, fmt.Errorf("%w: %v entries of size %v do not match %v bytes", ErrMalformedProof, count, size, len(body))
+	}
+
+	path := make([]AuditHash, count)
+	for i := range path {
+		entry := body[:1+size]
+		body = body[1+size:]
+		switch entry[0] {
+		case 0x00:
+		case 0x01:
+			path[i].RightOperator = true
+		default:
+			return nil, fmt.Errorf("%w: entry %v has direction %#x", ErrMalformedProof, i, entry[0])
+		}
+		path[i].Val = append([]byte(nil), entry[1:]...)
+	}
+	return path, nil
+}
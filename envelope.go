@@ -0,0 +1,98 @@
+package merkle
+
+import "fmt"
+
+// Every binary encoding of the package starts with the same envelope, so that a blob is never
+// decoded as another artifact or with the layout of another version:
+//
+//	[4]byte "MRKL"
+//	uint8   format version, 1
+//	uint8   kind of artifact the payload holds
+//	payload
+//
+// The layouts documented on the encoders are those of the payloads.
+
+// formatVersion is the version of the payload layouts.
+const formatVersion = 1
+
+// envelopeSize is the size of the envelope before the payload.
+const envelopeSize = 4 + 1 + 1
+
+var envelopeMagic = []byte("MRKL")
+
+// artifactKind identifies the artifact held by an envelope.
+type artifactKind uint8
+
+// The kinds of artifact encoded by the package.
+const (
+	kindProof artifactKind = iota + 1
+	kindCompactProof
+	kindInclusionProof
+	kindAuditHash
+	kindBundle
+	kindFrontier
+	kindSnapshot
+	kindTreeHead
+	kindProofChain
+)
+
+var kindNames = map[artifactKind]string{
+	kindProof:          "audit path",
+	kindCompactProof:   "compact proof",
+	kindInclusionProof: "inclusion proof",
+	kindAuditHash:      "audit hash",
+	kindBundle:         "bundle",
+	kindFrontier:       "frontier",
+	kindSnapshot:       "tree snapshot",
+	kindTreeHead:       "tree head",
+	kindProofChain:     "proof chain",
+}
+
+func (k artifactKind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("kind %v", uint8(k))
+}
+
+// envelopeHeader returns the envelope of an artifact of the given kind.
+func envelopeHeader(kind artifactKind) [envelopeSize]byte {
+	var header [envelopeSize]byte
+	copy(header[:], envelopeMagic)
+	header[4] = formatVersion
+	header[5] = byte(kind)
+	return header
+}
+
+// seal returns payload wrapped in the envelope of kind.
+func seal(kind artifactKind, payload []byte) []byte {
+	header := envelopeHeader(kind)
+	return append(header[:], payload...)
+}
+
+// unseal returns the payload of data, which must be wrapped in the envelope of kind.
+// This errors with ErrUnknownFormat when data does not start with the magic or holds another kind
+// of artifact, and with ErrUnknownFormatVersion when it was encoded with another format version.
+func unseal(kind artifactKind, data []byte) ([]byte, error) {
+	if err := checkEnvelope(kind, data); err != nil {
+		return nil, err
+	}
+	return data[envelopeSize:], nil
+}
+
+// checkEnvelope checks the envelope at the start of data, see unseal.
+func checkEnvelope(kind artifactKind, data []byte) error {
+	if len(data) < len(envelopeMagic) || string(data[:len(envelopeMagic)]) != string(envelopeMagic) {
+		return fmt.Errorf("%w: missing magic", ErrUnknownFormat)
+	}
+	if len(data) < envelopeSize {
+		return fmt.Errorf("%w: truncated envelope", ErrUnknownFormat)
+	}
+	if v := data[4]; v != formatVersion {
+		return fmt.Errorf("%w: version %v, expected %v", ErrUnknownFormatVersion, v, formatVersion)
+	}
+	if k := artifactKind(data[5]); k != kind {
+		return fmt.Errorf("%w: data holds a %v, expected a %v", ErrUnknownFormat, k, kind)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrIndexOutOfBounds is returned when an index does not address an item of the tree.
+	ErrIndexOutOfBounds = errors.New("merkle: index out of bounds")
+	// ErrEmptyTree is returned when an operation needs at least one item.
+	ErrEmptyTree = errors.New("merkle: empty tree")
+	// ErrInvalidHash is returned when a hash cannot be decoded or has the wrong size.
+	ErrInvalidHash = errors.New("merkle: invalid hash")
+	// ErrMalformedProof is returned when a serialized proof cannot be decoded.
+	ErrMalformedProof = errors.New("merkle: malformed proof")
+	// ErrHashMismatch is returned when a serialized artifact was produced with another hash function.
+	ErrHashMismatch = errors.New("merkle: hash function mismatch")
+	// ErrKeyNotFound is returned when a key is not set in a sparse tree.
+	ErrKeyNotFound = errors.New("merkle: key not found")
+	// ErrKeyExists is returned when proving the absence of a key that is set in a sparse tree or of a leaf of a sorted tree.
+	ErrKeyExists = errors.New("merkle: key exists")
+	// ErrBadPathLength is returned when an audit path does not have the length implied by its index and tree size.
+	ErrBadPathLength = errors.New("merkle: bad audit path length")
+	// ErrRootMismatch is returned when a proof does not lead to the expected root.
+	ErrRootMismatch = errors.New("merkle: root mismatch")
+	// ErrInvalidSnapshot is returned when a tree snapshot is corrupted or inconsistent.
+	ErrInvalidSnapshot = errors.New("merkle: invalid tree snapshot")
+	// ErrLeafNotFound is returned when looking up a leaf that is not in the tree.
+	ErrLeafNotFound = errors.New("merkle: leaf not found")
+	// ErrInvalidLeaf is returned when a typed item cannot be encoded into a leaf.
+	ErrInvalidLeaf = errors.New("merkle: invalid leaf")
+	// ErrDuplicateLeaf is returned when building a tree of unique leaves over repeated items.
+	ErrDuplicateLeaf = errors.New("merkle: duplicate leaf")
+	// ErrInvalidChunkSize is returned when splitting a stream into chunks that are not at least one byte long.
+	ErrInvalidChunkSize = errors.New("merkle: invalid chunk size")
+	// ErrPathTooLong is returned when an audit path has more entries than the limits of the Hasher allow.
+	ErrPathTooLong = errors.New("merkle: audit path too long")
+	// ErrLeafTooLarge is returned when a leaf is larger than the limits of the Hasher allow.
+	ErrLeafTooLarge = errors.New("merkle: leaf too large")
+	// ErrTreeTooLarge is returned when a proof declares a tree larger than the limits of the Hasher allow.
+	ErrTreeTooLarge = errors.New("merkle: tree too large")
+	// ErrMalformedTreeHead is returned when a tree head cannot be encoded or decoded.
+	ErrMalformedTreeHead = errors.New("merkle: malformed tree head")
+	// ErrInvalidSignature is returned when the signature of a tree head does not verify.
+	ErrInvalidSignature = errors.New("merkle: invalid signature")
+	// ErrUnknownFormat is returned when binary data does not hold the expected artifact of the package.
+	ErrUnknownFormat = errors.New("merkle: unknown format")
+	// ErrUnknownFormatVersion is returned when binary data was encoded with a format version this package does not know.
+	ErrUnknownFormatVersion = errors.New("merkle: unknown format version")
+	// ErrInvalidNodeHasher is returned when a NodeHasher fails the conformance checks of CheckNodeHasher.
+	ErrInvalidNodeHasher = errors.New("merkle: invalid node hasher")
+	// ErrTreeFull is returned when appending to a tree of fixed depth that holds as many leaves as it can.
+	ErrTreeFull = errors.New("merkle: tree full")
+	// ErrSumOverflow is returned when the amounts of a sum tree add up to more than a uint64 holds.
+	ErrSumOverflow = errors.New("merkle: sum overflow")
+	// ErrInvalidArity is returned when building a tree whose nodes cannot have the requested number of children.
+	ErrInvalidArity = errors.New("merkle: invalid arity")
+	// ErrBadHashSize is returned when a root or an entry of a proof does not have the digest size.
+	// It wraps ErrInvalidHash, which it refines.
+	ErrBadHashSize = fmt.Errorf("%w: bad hash size", ErrInvalidHash)
+)
+
+// indexError returns an ErrIndexOutOfBounds error describing index i in a tree of n items.
+func indexError(i, n int) error {
+	return fmt.Errorf("%w: index %v, tree has %v items", ErrIndexOutOfBounds, i, n)
+}
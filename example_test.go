@@ -0,0 +1,49 @@
+package merkle_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	merkle "github.com/actuallyachraf/go-merkle"
+)
+
+// The request a browser posts to prove that an item is included in a published tree, with the
+// audit path as AuditHash.MarshalJSON encodes it.
+const browserRequest = `{
+	"item": "bob",
+	"index": 1,
+	"tree_size": 3,
+	"path": [
+		{"side": "left", "hash": "761f5c77fbf20a3f9354ca49c5406987122fdf2162980df7b26466807c2a1348"},
+		{"side": "right", "hash": "bc88c474e2a8496adab25841d06929d448d6f54dd2d67ad30a175032e33e5bc3"}
+	]
+}`
+
+func ExampleHasher_UnmarshalPathJSON() {
+	var req struct {
+		Item     string          `json:"item"`
+		Index    int             `json:"index"`
+		TreeSize int             `json:"tree_size"`
+		Path     json.RawMessage `json:"path"`
+	}
+	if err := json.Unmarshal([]byte(browserRequest), &req); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	h := merkle.NewHasher()
+	root, _ := h.ParseRootHex("5297d217101add96f064968a2fe85dbfcf609dc5d07d2409a67b20b948ecbfcd")
+	path, err := h.UnmarshalPathJSON(req.Path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	p := merkle.InclusionProof{LeafIndex: req.Index, TreeSize: req.TreeSize, Path: path}
+	fmt.Println(h.VerifyInclusion(root, []byte(req.Item), p))
+
+	_, err = h.UnmarshalPathJSON([]byte(`[{"side": "left", "hash": "761f5c77"}]`))
+	fmt.Println(err)
+	// Output:
+	// <nil>
+	// merkle: malformed proof: entry 0 has a hash of 4 bytes, expected 32
+}
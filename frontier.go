@@ -0,0 +1,129 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// Frontier computes the root of an append only tree while keeping only O(log n) hashes:
+// the roots of the perfect subtrees the leaves decompose into, largest first, one for each
+// bit set in the number of leaves. Its root after n appends is the Root of the n leaves.
+//
+// The leaves and interior nodes are not kept, so a Frontier cannot produce inclusion or
+// consistency proofs, use a Tree for that.
+type Frontier struct {
+	h     *Hasher
+	size  int
+	nodes [][]byte
+}
+
+// NewFrontier returns the frontier of an empty tree, the options configure the hash function
+// as for NewHasher.
+func NewFrontier(opts ...Option) *Frontier {
+	return NewHasher(opts...).NewFrontier()
+}
+
+// NewFrontier returns the frontier of an empty tree using the Hasher's hash function.
+func (h *Hasher) NewFrontier() *Frontier {
+	return &Frontier{h: h}
+}
+
+// Size returns the number of leaves appended.
+func (f *Frontier) Size() int {
+	return f.size
+}
+
+// Append adds a leaf at the end of the tree.
+func (f *Frontier) Append(leaf []byte) {
+	node := f.h.LeafHashAt(f.size, leaf)
+	// Every trailing one bit of the size is a perfect subtree the new node completes.
+	for s := f.size; s&1 == 1; s >>= 1 {
+		node = f.h.NodeHash(f.nodes[len(f.nodes)-1], node)
+		f.nodes = f.nodes[:len(f.nodes)-1]
+	}
+	f.nodes = append(f.nodes, node)
+	f.size++
+}
+
+// Root returns the root hash of the tree over the appended leaves.
+func (f *Frontier) Root() []byte {
+	return f.h.bagFrontier(f.nodes)
+}
+
+// bagFrontier returns the root of a tree from the roots of its perfect subtrees, largest first.
+// The largest subtree is the left child of the root and the remaining ones make up its right child.
+func (h *Hasher) bagFrontier(nodes [][]byte) []byte {
+	if len(nodes) == 0 {
+		return h.emptyHash()
+	}
+	root := nodes[len(nodes)-1]
+	for i := len(nodes) - 2; i >= 0; i-- {
+		root = h.NodeHash(nodes[i], root)
+	}
+	return root
+}
+
+// frontierHeaderSize is the size of the hash identifier, digest size and leaf count fields of an exported frontier.
+const frontierHeaderSize = 10
+
+// Export encodes the state of the frontier in the envelope of the package with the following payload:
+//
+//	uint8   HashID of the hash function
+//	uint8   digest size
+//	uint64  number of leaves, big endian
+//	subtree roots, largest first, one for each bit set in the number of leaves
+func (f *Frontier) Export() []byte {
+	size := f.h.Size()
+	data := make([]byte, frontierHeaderSize, frontierHeaderSize+len(f.nodes)*size)
+	data[0] = byte(f.h.id)
+	data[1] = byte(size)
+	binary.BigEndian.PutUint64(data[2:], uint64(f.size))
+	for _, node := range f.nodes {
+		data = append(data, node...)
+	}
+	return seal(kindFrontier, data)
+}
+
+// ImportFrontier decodes a frontier exported by Export, the options configure the hash function
+// as for NewHasher and must be the ones of the exported frontier.
+func ImportFrontier(data []byte, opts ...Option) (*Frontier, error) {
+	return NewHasher(opts...).ImportFrontier(data)
+}
+
+// ImportFrontier decodes a frontier exported by Export using the Hasher's hash function.
+// This errors with ErrUnknownFormat or ErrUnknownFormatVersion when the data is not a frontier of
+// this format version, with ErrHashMismatch when the frontier was exported for another hash
+// function and with ErrInvalidSnapshot when the data is truncated, has trailing bytes or the wrong
+// digest size.
+func (h *Hasher) ImportFrontier(data []byte) (*Frontier, error) {
+	data, err := unseal(kindFrontier, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < frontierHeaderSize {
+		return nil, fmt.Errorf("%w: truncated header", ErrInvalidSnapshot)
+	}
+	if id := HashID(data[0]); id != h.id {
+		return nil, fmt.Errorf("%w: frontier uses hash %v, expected %v", ErrHashMismatch, id, h.id)
+	}
+	size := h.Size()
+	if int(data[1]) != size {
+		return nil, fmt.Errorf("%w: hash size %v, expected %v", ErrInvalidSnapshot, data[1], size)
+	}
+	n := binary.BigEndian.Uint64(data[2:])
+	if n > uint64(maxInt) {
+		return nil, fmt.Errorf("%w: %v leaves", ErrInvalidSnapshot, n)
+	}
+	count := bits.OnesCount64(n)
+	body := data[frontierHeaderSize:]
+	if len(body) != count*size {
+		return nil, fmt.Errorf("%w: %v subtree roots of size %v do not match %v bytes", ErrInvalidSnapshot, count, size, len(body))
+	}
+
+	f := &Frontier{h: h, size: int(n), nodes: make([][]byte, count)}
+	for i := range f.nodes {
+		f.nodes[i] = append([]byte(nil), body[i*size:(i+1)*size]...)
+	}
+	return f, nil
+}
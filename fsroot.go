@@ -0,0 +1,168 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Manifest lists the files committed to by RootFS, in the order of the leaves of the tree,
+// with the leaf hash of each file. Paths are slash separated and relative to the walked directory.
+type Manifest struct {
+	Paths      []string
+	LeafHashes [][]byte
+}
+
+// Index returns the leaf index of the file at path, and false when it is not in the manifest.
+func (m Manifest) Index(name string) (int, bool) {
+	i := sort.SearchStrings(m.Paths, name)
+	if i < len(m.Paths) && m.Paths[i] == name {
+		return i, true
+	}
+	return 0, false
+}
+
+// RootFS returns the root hash of the tree over the regular files below the directory root
+// of fsys, and the manifest of those files.
+//
+// The files are sorted by their slash separated path relative to root, byte by byte, which
+// does not depend on the operating system or the order of the directory entries. The leaf of a
+// file is its path and its contents, uint64 big endian length of the path || path || contents,
+// hashed as file contents are read so files are never held in memory. Empty files are leaves
+// like any other, a directory without files has the root of the empty tree.
+// This errors with ErrInvalidLeaf when the directory holds a symbolic link or any other file
+// that is not a regular file or a directory, and with the errors of fsys.
+func RootFS(fsys fs.FS, root string) ([]byte, Manifest, error) {
+	return defaultHasher.RootFS(fsys, root)
+}
+
+// RootFS returns the root hash and the manifest of the files below root using the Hasher's hash function.
+func (h *Hasher) RootFS(fsys fs.FS, root string) ([]byte, Manifest, error) {
+	var names []string
+	err := fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return nil
+		case !d.Type().IsRegular():
+			return fmt.Errorf("%w: %v is not a regular file", ErrInvalidLeaf, name)
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		return nil, Manifest{}, err
+	}
+
+	m := Manifest{Paths: make([]string, len(names)), LeafHashes: make([][]byte, len(names))}
+	for i, name := range names {
+		m.Paths[i] = relativePath(root, name)
+	}
+	sort.Sort(byPath{names, m.Paths})
+	for i, name := range names {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, Manifest{}, err
+		}
+		m.LeafHashes[i], err = h.fileLeafHash(m.Paths[i], f)
+		f.Close()
+		if err != nil {
+			return nil, Manifest{}, err
+		}
+	}
+
+	r, err := h.RootFromLeafHashes(m.LeafHashes)
+	return r, m, err
+}
+
+// ProveFile returns the inclusion proof of the file at path in the tree of a manifest built by RootFS.
+// This errors with ErrLeafNotFound when the file is not in the manifest.
+func ProveFile(m Manifest, name string) (InclusionProof, error) {
+	return defaultHasher.ProveFile(m, name)
+}
+
+// ProveFile returns the inclusion proof of a file of a manifest using the Hasher's hash function.
+func (h *Hasher) ProveFile(m Manifest, name string) (InclusionProof, error) {
+	i, ok := m.Index(name)
+	if !ok {
+		return InclusionProof{}, fmt.Errorf("%w: %v", ErrLeafNotFound, name)
+	}
+	path, err := h.ProofFromLeafHashes(m.LeafHashes, i)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	return InclusionProof{LeafIndex: i, TreeSize: len(m.LeafHashes), Path: path}, nil
+}
+
+// VerifyFile verifies that the file at path, whose contents are read from r, is included in
+// the tree whose root is root, as committed to by RootFS.
+// This errors like InclusionProof.Verify and with the errors of r.
+func VerifyFile(root []byte, name string, r io.Reader, p InclusionProof) error {
+	return defaultHasher.VerifyFile(root, name, r, p)
+}
+
+// VerifyFile verifies a file using the Hasher's hash function.
+func (h *Hasher) VerifyFile(root []byte, name string, r io.Reader, p InclusionProof) error {
+	if err := h.checkInclusion(root, p); err != nil {
+		return err
+	}
+	leaf, err := h.fileLeafHash(name, r)
+	if err != nil {
+		return err
+	}
+	if !h.VerifyLeafHash(root, leaf, p.LeafIndex, p.Path) {
+		return ErrRootMismatch
+	}
+	return nil
+}
+
+// fileLeafHash returns the leaf hash of the file at path whose contents are read from r.
+func (h *Hasher) fileLeafHash(name string, r io.Reader) ([]byte, error) {
+	if h.nodeHasher != nil {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		leaf := make([]byte, 8, 8+len(name)+len(data))
+		binary.BigEndian.PutUint64(leaf, uint64(len(name)))
+		return h.nodeHasher.HashLeaf(append(append(leaf, name...), data...)), nil
+	}
+	d := h.acquireLeaf()
+	defer h.leafPool.Put(d)
+
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(name)))
+	d.Write(h.leafPrefix)
+	d.Write(n[:])
+	io.WriteString(d, name)
+	if _, err := io.Copy(d, r); err != nil {
+		return nil, err
+	}
+	return d.Sum(nil), nil
+}
+
+// relativePath returns name relative to the directory root it was found in by fs.WalkDir.
+func relativePath(root, name string) string {
+	if root == "." {
+		return name
+	}
+	return strings.TrimPrefix(name, path.Clean(root)+"/")
+}
+
+// byPath sorts file names by their relative paths.
+type byPath struct {
+	names, paths []string
+}
+
+func (b byPath) Len() int           { return len(b.paths) }
+func (b byPath) Less(i, j int) bool { return b.paths[i] < b.paths[j] }
+func (b byPath) Swap(i, j int) {
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+	b.paths[i], b.paths[j] = b.paths[j], b.paths[i]
+}
@@ -0,0 +1,72 @@
+package merkle
+
+import "fmt"
+
+// The functions below work on typed items encoded into leaves by a caller supplied encoder,
+// which must be canonical: equal items encode to equal bytes. The encoder is called exactly
+// once per item and operation, a Tree built by TreeOf keeps the hashes of the encoded items.
+
+// RootOf returns the root hash of the tree over the encoded items.
+// This errors with ErrInvalidLeaf when encode is nil or returns nil.
+func RootOf[T any](items []T, encode func(T) []byte) ([]byte, error) {
+	leaves, err := encodeAll(items, encode)
+	if err != nil {
+		return nil, err
+	}
+	return Root(leaves), nil
+}
+
+// ProofOf returns the audit path of the item at index i.
+// This errors with ErrInvalidLeaf when encode is nil or returns nil and otherwise like Proof.
+func ProofOf[T any](items []T, i int, encode func(T) []byte) ([]AuditHash, error) {
+	leaves, err := encodeAll(items, encode)
+	if err != nil {
+		return nil, err
+	}
+	return Proof(leaves, i)
+}
+
+// VerifyOf verifies that item is included at index in the tree whose root hash is root, like VerifyProof.
+// This errors with ErrInvalidLeaf when encode is nil or returns nil.
+func VerifyOf[T any](root []byte, item T, index int, path []AuditHash, encode func(T) []byte) (bool, error) {
+	leaf, err := encodeOne(item, encode)
+	if err != nil {
+		return false, err
+	}
+	return VerifyProof(root, leaf, index, path), nil
+}
+
+// TreeOf builds a tree over the encoded items, the options configure the hash function as for NewHasher.
+// This errors with ErrInvalidLeaf when encode is nil or returns nil.
+func TreeOf[T any](items []T, encode func(T) []byte, opts ...Option) (*Tree, error) {
+	leaves, err := encodeAll(items, encode)
+	if err != nil {
+		return nil, err
+	}
+	return NewTree(leaves, opts...), nil
+}
+
+func encodeAll[T any](items []T, encode func(T) []byte) ([][]byte, error) {
+	if encode == nil {
+		return nil, fmt.Errorf("%w: nil encoder", ErrInvalidLeaf)
+	}
+	leaves := make([][]byte, len(items))
+	for i, item := range items {
+		leaves[i] = encode(item)
+		if leaves[i] == nil {
+			return nil, fmt.Errorf("%w: item %v encoded to nil", ErrInvalidLeaf, i)
+		}
+	}
+	return leaves, nil
+}
+
+func encodeOne[T any](item T, encode func(T) []byte) ([]byte, error) {
+	if encode == nil {
+		return nil, fmt.Errorf("%w: nil encoder", ErrInvalidLeaf)
+	}
+	leaf := encode(item)
+	if leaf == nil {
+		return nil, fmt.Errorf("%w: item encoded to nil", ErrInvalidLeaf)
+	}
+	return leaf, nil
+}
@@ -1,3 +1,3 @@
 module github.com/actuallyachraf/go-merkle
 
-go 1.13
+go 1.18
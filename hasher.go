@@ -0,0 +1,362 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/actuallyachraf/go-merkle/internal/blake3"
+)
+
+// Hasher builds and verifies merkle trees using a configurable hash function.
+// The zero value is not usable, Hashers are created with NewHasher.
+type Hasher struct {
+	id             HashID
+	newHash        func() hash.Hash
+	newLeafHash    func() hash.Hash // hash function of the leaves when it is not newHash
+	nodeHasher     NodeHasher       // hashes leaves and nodes in place of newHash when set
+	leafPrefix     []byte
+	interiorPrefix []byte
+	sortPairs      bool // hash the smaller child first, the position of a node is then irrelevant
+	unsafePrefixes bool // allow prefixes that do not separate leaves from interior nodes
+	noLeafIndex    bool // build trees without the index of their leaf hashes
+	indexedLeaves  bool // bind the index of a leaf into its leaf hash
+	leafEncoding   LeafEncoding
+	progress       func(done, total int)
+	progressEvery  int
+	limits         Limits
+
+	size     int
+	empty    []byte
+	pool     sync.Pool // hash states reused across calls, always Reset before use
+	leafPool sync.Pool // the same for the hash function of the leaves
+	bufs     sync.Pool // *[]byte scratch digests of size bytes used by the verifiers
+}
+
+// HashID identifies the hash function of a Hasher in serialized proofs,
+// so that a proof is never decoded for a different hash function.
+type HashID uint8
+
+// The hash functions known to the package.
+const (
+	HashCustom HashID = iota // a hash function set with WithHash
+	HashSHA3_256
+	HashKeccak256
+	HashBLAKE3
+	HashSHA256
+	HashCSHAKE256
+)
+
+// Option configures a Hasher.
+type Option func(*Hasher)
+
+// WithHash sets the hash function used for leaves, interior nodes and the empty tree.
+// Proofs serialized by the Hasher are marked with HashCustom.
+// Digests are carried as slices of the size of the hash function, such as 64 bytes for SHA3-512
+// or 20 for SHA-1, and every root and audit hash is checked against that size. The binary
+// encodings hold digests of at most 255 bytes.
+func WithHash(newHash func() hash.Hash) Option {
+	return withNamedHash(HashCustom, newHash)
+}
+
+// withNamedHash sets one of the hash functions known to the package.
+func withNamedHash(id HashID, newHash func() hash.Hash) Option {
+	return func(h *Hasher) {
+		h.id = id
+		h.newHash = newHash
+		h.newLeafHash = nil
+		h.nodeHasher = nil
+	}
+}
+
+// WithKeccak256 hashes with the legacy Keccak-256 used by Ethereum, which differs from SHA3-256
+// by its padding. The tree keeps its domain separation prefixes, so the empty tree hashes to
+// keccak256("") and a single leaf to keccak256(0x00 || leaf).
+func WithKeccak256() Option {
+	return withNamedHash(HashKeccak256, sha3.NewLegacyKeccak256)
+}
+
+// WithBLAKE3 hashes with BLAKE3, which is considerably faster than SHA3-256 in pure Go.
+func WithBLAKE3() Option {
+	return withNamedHash(HashBLAKE3, blake3.New)
+}
+
+// WithSHA256 hashes with SHA-256, which together with the 0x00 and 0x01 prefixes makes the
+// trees, audit paths and consistency proofs those of RFC 6962 used by Certificate Transparency.
+func WithSHA256() Option {
+	return withNamedHash(HashSHA256, sha256.New)
+}
+
+// WithPrefixes sets the domain separation prefixes written before the data of a leaf and the
+// children of an interior node, which default to LeafPrefix and InteriorPrefix.
+// Neither prefix may be a prefix of the other, otherwise a leaf could be passed off as an
+// interior node, NewHasher panics on such prefixes unless WithUnsafePrefixes is also given.
+// The empty tree still hashes to H(""), it does not depend on the prefixes.
+func WithPrefixes(leaf, interior []byte) Option {
+	leaf = append([]byte{}, leaf...)
+	interior = append([]byte{}, interior...)
+	return func(h *Hasher) {
+		h.leafPrefix = leaf
+		h.interiorPrefix = interior
+	}
+}
+
+// WithUnsafePrefixes allows prefixes set with WithPrefixes that do not separate leaves from
+// interior nodes, such as two empty prefixes. Trees built this way are open to second preimage
+// attacks and must only be used to interoperate with systems that hash without prefixes.
+func WithUnsafePrefixes() Option {
+	return func(h *Hasher) {
+		h.unsafePrefixes = true
+	}
+}
+
+// WithInsecureRawNodeHashing hashes leaves as H(data) and interior nodes as H(left || right),
+// without any domain separation prefix, to verify the roots and proofs of legacy tools computed
+// that way. Such trees are open to second preimage attacks: an interior node is the hash of the
+// leaf left || right, so a proof of that leaf one level up verifies for data that was never a
+// leaf. It must never be used for new trees, the prefixes given by WithPrefixes are replaced.
+func WithInsecureRawNodeHashing() Option {
+	return func(h *Hasher) {
+		h.leafPrefix = nil
+		h.interiorPrefix = nil
+		h.unsafePrefixes = true
+	}
+}
+
+// WithLeafIndexBinding hashes the leaf at index i as H(0x00 || uint64BE(i) || data), with the
+// default prefix, so that the same data at two positions has two different leaf hashes and a
+// proof of one occurrence never verifies at the index of another.
+// The index is bound by Root, NewTree, Proof, Tree.Append and Tree.Update and by the verifiers
+// of audit paths, inclusion, batch, consistency, multi and range proofs, which all know the
+// index of the leaves they hash. Sets, MMRs and the other structures that do not place leaves
+// by index keep the unbound LeafHash. The roots are not those of the default mode.
+// Trees built this way cannot be merged, and the lookups of their leaves by content hash the
+// leaf at every index.
+func WithLeafIndexBinding() Option {
+	return func(h *Hasher) {
+		h.indexedLeaves = true
+	}
+}
+
+// LeafEncoding is the way the data of a leaf is written after its prefix when hashing it.
+type LeafEncoding uint8
+
+// The leaf encodings.
+const (
+	LeafRaw            LeafEncoding = iota // the data as it is, H(0x00 || data)
+	LeafLengthPrefixed                     // the length of the data as a uvarint before it, H(0x00 || uvarint(len(data)) || data)
+)
+
+// WithLeafEncoding sets the encoding of the data of the leaves, which defaults to LeafRaw.
+// LeafLengthPrefixed makes the encoding injective for leaves made of concatenated fields,
+// the length being encoded by binary.PutUvarint. It applies to every leaf the Hasher hashes
+// from its data, after the index of WithLeafIndexBinding, and its roots are not those of LeafRaw.
+func WithLeafEncoding(e LeafEncoding) Option {
+	return func(h *Hasher) {
+		h.leafEncoding = e
+	}
+}
+
+// WithoutLeafIndex builds trees without the index from leaf hashes to indices they keep by default,
+// which costs a map entry per distinct leaf, keyed by its leaf hash. The lookups by leaf content of
+// such trees, such as Tree.IndexOf, scan every leaf instead.
+func WithoutLeafIndex() Option {
+	return func(h *Hasher) {
+		h.noLeafIndex = true
+	}
+}
+
+// defaultHasher backs the package level functions.
+var defaultHasher = NewHasher()
+
+// namedHashers holds a Hasher with the default prefixes for each hash function known to the package.
+var namedHashers = map[HashID]*Hasher{
+	HashSHA3_256:  defaultHasher,
+	HashKeccak256: NewHasher(WithKeccak256()),
+	HashBLAKE3:    NewHasher(WithBLAKE3()),
+	HashSHA256:    NewHasher(WithSHA256()),
+	HashCSHAKE256: NewHasher(WithCSHAKE256()),
+}
+
+// hasherFor returns the Hasher with the default prefixes for a hash function known to the package.
+// This errors with ErrHashMismatch for HashCustom and unknown identifiers.
+func hasherFor(id HashID) (*Hasher, error) {
+	h, ok := namedHashers[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown hash %v", ErrHashMismatch, id)
+	}
+	return h, nil
+}
+
+// NewHasher returns a Hasher configured by opts, it defaults to SHA3-256.
+// It panics when the prefixes set with WithPrefixes are unsafe, see WithUnsafePrefixes.
+func NewHasher(opts ...Option) *Hasher {
+	h := &Hasher{
+		id:             HashSHA3_256,
+		newHash:        sha3.New256,
+		leafPrefix:     leafPrefix,
+		interiorPrefix: interiorPrefix,
+		limits:         DefaultLimits,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if !h.unsafePrefixes && (bytes.HasPrefix(h.leafPrefix, h.interiorPrefix) || bytes.HasPrefix(h.interiorPrefix, h.leafPrefix)) {
+		panic("merkle: the leaf and interior prefixes must not be prefixes of each other")
+	}
+	if h.nodeHasher != nil {
+		h.initNodeHasher()
+		return h
+	}
+	h.pool.New = func() interface{} { return h.newHash() }
+	newLeafHash := h.newHash
+	if h.newLeafHash != nil {
+		newLeafHash = h.newLeafHash
+	}
+	h.leafPool.New = func() interface{} { return newLeafHash() }
+	h.size = h.newHash().Size()
+	h.bufs.New = func() interface{} {
+		b := make([]byte, 0, h.size)
+		return &b
+	}
+	h.empty = h.hash(nil)
+	return h
+}
+
+// HashID returns the identifier of the Hasher's hash function.
+func (h *Hasher) HashID() HashID {
+	return h.id
+}
+
+// Size returns the size in bytes of the digests produced by the Hasher.
+func (h *Hasher) Size() int {
+	return h.size
+}
+
+// emptyHash returns the root of an empty tree, the hash of the empty string.
+func (h *Hasher) emptyHash() []byte {
+	return append([]byte{}, h.empty...)
+}
+
+// LeafHash returns the hash of a leaf, H(0x00 || data) with the default prefixes.
+func (h *Hasher) LeafHash(data []byte) []byte {
+	return h.leafHashTo(nil, data)
+}
+
+// LeafHashAt returns the hash of the leaf at index i, which is LeafHash(data) unless the Hasher
+// was created WithLeafIndexBinding.
+func (h *Hasher) LeafHashAt(i int, data []byte) []byte {
+	return h.leafHashAt(nil, i, data)
+}
+
+// NodeHash returns the hash of an interior node, H(0x01 || left || right) with the default prefixes.
+func (h *Hasher) NodeHash(left, right []byte) []byte {
+	return h.nodeHashTo(nil, left, right)
+}
+
+// leafHashTo appends the hash of a leaf to dst.
+func (h *Hasher) leafHashTo(dst, data []byte) []byte {
+	if h.nodeHasher != nil {
+		return h.nodeHasherLeaf(dst, nil, data)
+	}
+	d := h.acquireLeaf()
+	d.Write(h.leafPrefix)
+	h.writeLeafData(d, dst, data)
+	dst = d.Sum(dst)
+	h.leafPool.Put(d)
+	return dst
+}
+
+// leafHashAt appends the hash of the leaf at index i to dst.
+func (h *Hasher) leafHashAt(dst []byte, i int, data []byte) []byte {
+	if !h.indexedLeaves {
+		return h.leafHashTo(dst, data)
+	}
+	// The index is laid out past the end of dst, where the digest goes once the index is written.
+	index := spare(dst, 8)
+	binary.BigEndian.PutUint64(index, uint64(i))
+	if h.nodeHasher != nil {
+		return h.nodeHasherLeaf(dst, index, data)
+	}
+	d := h.acquireLeaf()
+	d.Write(h.leafPrefix)
+	d.Write(index)
+	h.writeLeafData(d, dst, data)
+	dst = d.Sum(dst)
+	h.leafPool.Put(d)
+	return dst
+}
+
+// writeLeafData writes the data of a leaf to d in the Hasher's LeafEncoding, laying out its
+// length past the end of dst.
+func (h *Hasher) writeLeafData(d hash.Hash, dst, data []byte) {
+	if h.leafEncoding == LeafLengthPrefixed {
+		n := spare(dst, binary.MaxVarintLen64)
+		d.Write(n[:binary.PutUvarint(n, uint64(len(data)))])
+	}
+	d.Write(data)
+}
+
+// spare returns n bytes past the end of dst, in its capacity when it has room, so that writing
+// small fields to a hash state whose sum is then appended to dst does not allocate: the fields
+// escape through the hash.Hash interface.
+func spare(dst []byte, n int) []byte {
+	return append(dst, make([]byte, n)...)[len(dst):]
+}
+
+// nodeHashTo appends the hash of an interior node to dst.
+// The children are written to the hash state before dst, so dst may share their storage.
+func (h *Hasher) nodeHashTo(dst, left, right []byte) []byte {
+	if h.sortPairs && bytes.Compare(left, right) > 0 {
+		left, right = right, left
+	}
+	if h.nodeHasher != nil {
+		return append(dst, h.nodeHasher.HashNode(left, right)...)
+	}
+	d := h.acquire()
+	d.Write(h.interiorPrefix)
+	d.Write(left)
+	d.Write(right)
+	dst = d.Sum(dst)
+	h.pool.Put(d)
+	return dst
+}
+
+// hash returns the hash of a, the leaf hash of a with a NodeHasher, which has no plain hash.
+func (h *Hasher) hash(a []byte) []byte {
+	if h.nodeHasher != nil {
+		return h.nodeHasher.HashLeaf(a)
+	}
+	d := h.newHash()
+	d.Write(a)
+	return d.Sum(nil)
+}
+
+// scratch returns an empty digest buffer with room for one digest, to be put back with release
+// once nothing refers to it.
+func (h *Hasher) scratch() *[]byte {
+	return h.bufs.Get().(*[]byte)
+}
+
+func (h *Hasher) release(b *[]byte) {
+	h.bufs.Put(b)
+}
+
+// acquire returns a reset hash state from the pool, to be put back once its sum is taken.
+func (h *Hasher) acquire() hash.Hash {
+	d := h.pool.Get().(hash.Hash)
+	d.Reset()
+	return d
+}
+
+// acquireLeaf returns a reset hash state of the hash function of the leaves, to be put back in leafPool.
+func (h *Hasher) acquireLeaf() hash.Hash {
+	d := h.leafPool.Get().(hash.Hash)
+	d.Reset()
+	return d
+}
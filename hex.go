@@ -0,0 +1,160 @@
+package merkle
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	hexLeft  = "L:"
+	hexRight = "R:"
+)
+
+// RootHex returns the root hash of the items as a lowercase hex string.
+func RootHex(items [][]byte) string {
+	return defaultHasher.RootHex(items)
+}
+
+// RootHex returns the root hash of the items computed with the Hasher's hash function
+// as a lowercase hex string.
+func (h *Hasher) RootHex(items [][]byte) string {
+	return hexify(h.Root(items))
+}
+
+// DecodeHash decodes a hex encoded digest of the default hash function, such as a root or a leaf hash.
+// This errors with ErrInvalidHash when s has an odd length or a character that is not hex, and
+// with ErrBadHashSize when it does not hold a digest of the default hash function.
+func DecodeHash(s string) ([]byte, error) {
+	return defaultHasher.DecodeHash(s)
+}
+
+// DecodeHash decodes a hex encoded digest of the Hasher's hash function.
+func (h *Hasher) DecodeHash(s string) ([]byte, error) {
+	d, err := unhexify(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	if len(d) != h.Size() {
+		return nil, fmt.Errorf("%w: %v bytes, expected %v", ErrBadHashSize, len(d), h.Size())
+	}
+	return d, nil
+}
+
+// ParseRootHex decodes a hex encoded root hash.
+// This errors like DecodeHash.
+func ParseRootHex(s string) ([]byte, error) {
+	return defaultHasher.ParseRootHex(s)
+}
+
+// ParseRootHex decodes a hex encoded root hash of the Hasher's hash function.
+func (h *Hasher) ParseRootHex(s string) ([]byte, error) {
+	return h.DecodeHash(s)
+}
+
+// EncodeProofHex encodes each entry of an audit path as "L:<hex>" or "R:<hex>",
+// where the letter tells on which side of the concatenation the hash goes.
+func EncodeProofHex(path []AuditHash) []string {
+	res := make([]string, len(path))
+	for i, entry := range path {
+		side := hexLeft
+		if entry.RightOperator {
+			side = hexRight
+		}
+		res[i] = side + hexify(entry.Val)
+	}
+	return res
+}
+
+// DecodeProofHex decodes an audit path encoded by EncodeProofHex.
+// This errors with ErrMalformedProof, naming the bad entry, when an entry has no side or when its hash is empty,
+// not valid hex or larger than maxHashSize, and with ErrPathTooLong when there are more entries than
+// DefaultLimits allow.
+func DecodeProofHex(entries []string) ([]AuditHash, error) {
+	return defaultHasher.decodeProofHex(entries)
+}
+
+// DecodeProofHex decodes an audit path encoded by EncodeProofHex within the Hasher's Limits, every
+// hash must have the size of the Hasher's digests.
+// This errors like the package level DecodeProofHex and with ErrMalformedProof when a hash does
+// not have the digest size.
+func (h *Hasher) DecodeProofHex(entries []string) ([]AuditHash, error) {
+	path, err := h.decodeProofHex(entries)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range path {
+		if len(entry.Val) != h.Size() {
+			return nil, fmt.Errorf("%w: entry %v has a hash of %v bytes, expected %v", ErrMalformedProof, i, len(entry.Val), h.Size())
+		}
+	}
+	return path, nil
+}
+
+// decodeProofHex decodes an audit path encoded by EncodeProofHex with hashes of any size, within
+// the Hasher's Limits.
+func (h *Hasher) decodeProofHex(entries []string) ([]AuditHash, error) {
+	if err := h.checkPathLen(len(entries)); err != nil {
+		return nil, err
+	}
+	path := make([]AuditHash, len(entries))
+	for i, entry := range entries {
+		switch {
+		case strings.HasPrefix(entry, hexLeft):
+		case strings.HasPrefix(entry, hexRight):
+			path[i].RightOperator = true
+		default:
+			return nil, fmt.Errorf("%w: entry %v has no side", ErrMalformedProof, i)
+		}
+
+		s := entry[len(hexLeft):]
+		if len(s) == 0 || len(s) > 2*maxHashSize {
+			return nil, fmt.Errorf("%w: entry %v has a hash of %v hex characters", ErrMalformedProof, i, len(s))
+		}
+		val, err := unhexify(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %v: %v", ErrMalformedProof, i, err)
+		}
+		path[i].Val = val
+	}
+	return path, nil
+}
+
+// FormatPath formats an audit path as its EncodeProofHex entries separated by semicolons,
+// for example "R:ab12...;L:cd34...". The empty path formats as the empty string.
+func FormatPath(path []AuditHash) string {
+	return strings.Join(EncodeProofHex(path), ";")
+}
+
+// ParsePath parses an audit path formatted by FormatPath for the default hash function.
+func ParsePath(s string) ([]AuditHash, error) {
+	return defaultHasher.ParsePath(s)
+}
+
+// ParsePath parses an audit path formatted by FormatPath, every hash must have the size of the Hasher's digests.
+// This errors with ErrMalformedProof naming the first bad segment when a segment is empty, has a side
+// other than L or R, or a hash that is not valid hex of the digest size, and with ErrPathTooLong
+// before splitting s when it has more segments than the Hasher's Limits allow.
+func (h *Hasher) ParsePath(s string) ([]AuditHash, error) {
+	if s == "" {
+		return []AuditHash{}, nil
+	}
+	if err := h.checkPathLen(strings.Count(s, ";") + 1); err != nil {
+		return nil, err
+	}
+	segments := strings.Split(s, ";")
+	for i, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("%w: segment %v is empty", ErrMalformedProof, i)
+		}
+	}
+	path, err := h.decodeProofHex(segments)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range path {
+		if len(entry.Val) != h.Size() {
+			return nil, fmt.Errorf("%w: segment %v has a hash of %v bytes, expected %v", ErrMalformedProof, i, len(entry.Val), h.Size())
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,165 @@
+package merkle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// errBadQuery is returned when the query of a request is missing or cannot be parsed.
+var errBadQuery = errors.New("merkle: bad query")
+
+// rootResponse is the body of a /root response.
+type rootResponse struct {
+	TreeSize int    `json:"tree_size"`
+	Root     string `json:"root"`
+}
+
+// proofResponse is the body of a /proof response.
+type proofResponse struct {
+	TreeSize  int         `json:"tree_size"`
+	LeafIndex int         `json:"leaf_index"`
+	Root      string      `json:"root"`
+	Path      []AuditHash `json:"path"`
+}
+
+// consistencyResponse is the body of a /consistency response.
+type consistencyResponse struct {
+	OldSize  int         `json:"old_size"`
+	TreeSize int         `json:"tree_size"`
+	OldRoot  string      `json:"old_root"`
+	Root     string      `json:"root"`
+	Proof    []AuditHash `json:"proof"`
+}
+
+// errorResponse is the body of a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHTTPHandler returns an http.Handler serving the root and the proofs of tree as JSON, the
+// hashes being lowercase hex and the audit hashes encoded as by AuditHash.MarshalJSON:
+//
+//	GET /root                 {"tree_size", "root"}
+//	GET /proof?index=N        {"tree_size", "leaf_index", "root", "path"}
+//	GET /proof?leaf=<hex>     the same for the first leaf whose data is the decoded hex
+//	GET /consistency?old=M    {"old_size", "tree_size", "old_root", "root", "proof"}
+//
+// Each response is computed under a single read lock of the tree, so its root, size and proof
+// agree while the tree is appended to concurrently. A bad index, size or hex parameter is answered
+// with 400, a leaf that is not in the tree with 404 and other methods than GET with 405, errors
+// having the body {"error"}.
+func NewHTTPHandler(tree *Tree) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/root", getOnly(func(w http.ResponseWriter, r *http.Request) {
+		tree.mu.RLock()
+		res := rootResponse{TreeSize: tree.leafCount(), Root: hexify(tree.root())}
+		tree.mu.RUnlock()
+		writeJSON(w, http.StatusOK, res)
+	}))
+	mux.HandleFunc("/proof", getOnly(func(w http.ResponseWriter, r *http.Request) {
+		res, err := serveProof(tree, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}))
+	mux.HandleFunc("/consistency", getOnly(func(w http.ResponseWriter, r *http.Request) {
+		res, err := serveConsistency(tree, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}))
+	return mux
+}
+
+func serveProof(t *Tree, r *http.Request) (proofResponse, error) {
+	q := r.URL.Query()
+	index, hasIndex := q["index"]
+	leaf, hasLeaf := q["leaf"]
+	if hasIndex == hasLeaf {
+		return proofResponse{}, fmt.Errorf("%w: exactly one of index and leaf must be given", errBadQuery)
+	}
+
+	var i int
+	var data []byte
+	var err error
+	if hasIndex {
+		if i, err = strconv.Atoi(index[0]); err != nil {
+			return proofResponse{}, fmt.Errorf("%w: index %q", errBadQuery, index[0])
+		}
+	} else if data, err = unhexify(leaf[0]); err != nil {
+		return proofResponse{}, fmt.Errorf("%w: leaf: %v", errBadQuery, err)
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if hasLeaf {
+		indices := t.indicesOfLeaf(data)
+		if len(indices) == 0 {
+			return proofResponse{}, ErrLeafNotFound
+		}
+		i = indices[0]
+	}
+	path, err := t.proof(i)
+	if err != nil {
+		return proofResponse{}, err
+	}
+	return proofResponse{TreeSize: t.leafCount(), LeafIndex: i, Root: hexify(t.root()), Path: path}, nil
+}
+
+func serveConsistency(t *Tree, r *http.Request) (consistencyResponse, error) {
+	old, err := strconv.Atoi(r.URL.Query().Get("old"))
+	if err != nil {
+		return consistencyResponse{}, fmt.Errorf("%w: old size %q", errBadQuery, r.URL.Query().Get("old"))
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	n := t.leafCount()
+	if old < 0 || old > n {
+		return consistencyResponse{}, fmt.Errorf("%w: old size %v, tree has %v items", ErrIndexOutOfBounds, old, n)
+	}
+	res := consistencyResponse{OldSize: old, TreeSize: n, OldRoot: hexify(t.h.emptyHash()), Root: hexify(t.root()), Proof: []AuditHash{}}
+	if old > 0 {
+		res.OldRoot = hexify(t.subtreeNode(0, old))
+	}
+	if old > 0 && old < n {
+		res.Proof = t.subproof(old, 0, n, true)
+	}
+	return res, nil
+}
+
+// getOnly answers requests with other methods than GET with 405.
+func getOnly(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+			return
+		}
+		fn(w, r)
+	}
+}
+
+// writeError answers a failed request with the status matching err.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, ErrLeafNotFound) {
+		status = http.StatusNotFound
+	} else if !errors.Is(err, ErrIndexOutOfBounds) && !errors.Is(err, ErrEmptyTree) && !errors.Is(err, errBadQuery) {
+		status = http.StatusInternalServerError
+	}
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,98 @@
+package merkle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// get serves a request to the handler and decodes its body into v, returning the status.
+func get(t *testing.T, h http.Handler, method, target string, v interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%v %v: Content-Type %q", method, target, ct)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("%v %v: %v in %q", method, target, err, rec.Body)
+	}
+	return rec.Code
+}
+
+func TestHTTPHandler(t *testing.T) {
+	items := testItems(7)
+	tree := NewTree(items)
+	h := NewHTTPHandler(tree)
+	root := tree.Root()
+
+	var r rootResponse
+	if code := get(t, h, http.MethodGet, "/root", &r); code != http.StatusOK || r.TreeSize != 7 || r.Root != hexify(root) {
+		t.Errorf("/root: %v %+v", code, r)
+	}
+
+	for _, target := range []string{"/proof?index=5", "/proof?leaf=" + hexify(items[5])} {
+		var p proofResponse
+		if code := get(t, h, http.MethodGet, target, &p); code != http.StatusOK {
+			t.Fatalf("%v: status %v", target, code)
+		}
+		if p.TreeSize != 7 || p.LeafIndex != 5 || p.Root != hexify(root) {
+			t.Errorf("%v: %+v", target, p)
+		}
+		if err := VerifyE(root, items[5], p.LeafIndex, p.Path); err != nil {
+			t.Errorf("%v: %v", target, err)
+		}
+	}
+
+	for old := 0; old <= 7; old++ {
+		var c consistencyResponse
+		target := "/consistency?old=" + strconv.Itoa(old)
+		if code := get(t, h, http.MethodGet, target, &c); code != http.StatusOK {
+			t.Fatalf("%v: status %v", target, code)
+		}
+		if c.OldSize != old || c.TreeSize != 7 || c.Root != hexify(root) || c.OldRoot != hexify(Root(items[:old])) {
+			t.Errorf("%v: %+v", target, c)
+		}
+		if old > 0 && !VerifyConsistency(Root(items[:old]), root, old, 7, c.Proof) {
+			t.Errorf("%v: the proof does not verify", target)
+		}
+	}
+
+	for _, c := range []struct {
+		method, target string
+		code           int
+	}{
+		{http.MethodPost, "/root", http.StatusMethodNotAllowed},
+		{http.MethodPut, "/proof?index=0", http.StatusMethodNotAllowed},
+		{http.MethodDelete, "/consistency?old=1", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/proof", http.StatusBadRequest},
+		{http.MethodGet, "/proof?index=1&leaf=00", http.StatusBadRequest},
+		{http.MethodGet, "/proof?index=x", http.StatusBadRequest},
+		{http.MethodGet, "/proof?index=7", http.StatusBadRequest},
+		{http.MethodGet, "/proof?index=-1", http.StatusBadRequest},
+		{http.MethodGet, "/proof?leaf=zz", http.StatusBadRequest},
+		{http.MethodGet, "/proof?leaf=" + hexify([]byte("not a leaf")), http.StatusNotFound},
+		{http.MethodGet, "/consistency", http.StatusBadRequest},
+		{http.MethodGet, "/consistency?old=8", http.StatusBadRequest},
+		{http.MethodGet, "/consistency?old=-1", http.StatusBadRequest},
+	} {
+		var e errorResponse
+		if code := get(t, h, c.method, c.target, &e); code != c.code || e.Error == "" {
+			t.Errorf("%v %v: status %v, error %q, want status %v", c.method, c.target, code, e.Error, c.code)
+		}
+	}
+}
+
+func TestHTTPHandlerEmptyTree(t *testing.T) {
+	h := NewHTTPHandler(NewTree(nil))
+	var r rootResponse
+	if code := get(t, h, http.MethodGet, "/root", &r); code != http.StatusOK || r.TreeSize != 0 || r.Root != hexify(Root(nil)) {
+		t.Errorf("/root: %v %+v", code, r)
+	}
+	var e errorResponse
+	if code := get(t, h, http.MethodGet, "/proof?index=0", &e); code != http.StatusBadRequest {
+		t.Errorf("/proof?index=0: status %v, want %v", code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,98 @@
+package merkle
+
+import "fmt"
+
+// InclusionProof is an audit path together with the index of the leaf it proves and the size
+// of the tree it was generated for, which is everything a verifier needs besides the root and the leaf.
+type InclusionProof struct {
+	LeafIndex int
+	TreeSize  int
+	Path      []AuditHash
+}
+
+// Prove returns the inclusion proof of the item at index i.
+// This errors with ErrEmptyTree when there are no items and ErrIndexOutOfBounds
+// when the requested index is out of bounds.
+func Prove(items [][]byte, i int) (InclusionProof, error) {
+	return defaultHasher.Prove(items, i)
+}
+
+// Prove returns the inclusion proof of the item at index i using the Hasher's hash function.
+func (h *Hasher) Prove(items [][]byte, i int) (InclusionProof, error) {
+	path, err := h.Proof(items, i)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	return InclusionProof{LeafIndex: i, TreeSize: len(items), Path: path}, nil
+}
+
+// Verify verifies that leaf is included in the tree whose root is root.
+// The length of the path is checked against the index and tree size before hashing anything.
+// This errors like Hasher.VerifyInclusion.
+func (p InclusionProof) Verify(root, leaf []byte) error {
+	return defaultHasher.VerifyInclusion(root, leaf, p)
+}
+
+// VerifyInclusion verifies an inclusion proof using the Hasher's hash function.
+// The structure of the proof is checked before hashing anything, so a malformed proof costs
+// no more than reading it.
+// This errors with ErrEmptyTree, ErrIndexOutOfBounds, ErrBadPathLength, ErrBadHashSize when the
+// root or an entry of the path does not have the digest size, ErrMalformedProof when an entry is
+// not on the side it has for the index of the proof, or ErrRootMismatch, and with
+// ErrTreeTooLarge, ErrPathTooLong or ErrLeafTooLarge when the proof exceeds the Hasher's Limits.
+func (h *Hasher) VerifyInclusion(root, leaf []byte, p InclusionProof) error {
+	if err := h.checkInclusion(root, p); err != nil {
+		return err
+	}
+	return h.VerifyE(root, leaf, p.LeafIndex, p.Path)
+}
+
+// checkInclusion checks the structure of an inclusion proof, then the size of root.
+func (h *Hasher) checkInclusion(root []byte, p InclusionProof) error {
+	if err := h.checkInclusionShape(p); err != nil {
+		return err
+	}
+	if len(root) != h.Size() {
+		return fmt.Errorf("%w: root has size %v, expected %v", ErrBadHashSize, len(root), h.Size())
+	}
+	return nil
+}
+
+// checkInclusionShape checks the structure of an inclusion proof: its index, size and the entries
+// of its path, each on the side of the tree of that size.
+func (h *Hasher) checkInclusionShape(p InclusionProof) error {
+	if p.TreeSize <= 0 {
+		return ErrEmptyTree
+	}
+	if err := h.checkTreeSize(p.TreeSize); err != nil {
+		return err
+	}
+	if err := h.checkPathLen(len(p.Path)); err != nil {
+		return err
+	}
+	if p.LeafIndex < 0 || p.LeafIndex >= p.TreeSize {
+		return indexError(p.LeafIndex, p.TreeSize)
+	}
+	if want := ProofLen(p.LeafIndex, p.TreeSize); len(p.Path) != want {
+		return fmt.Errorf("%w: %v entries for index %v of %v, expected %v", ErrBadPathLength, len(p.Path), p.LeafIndex, p.TreeSize, want)
+	}
+	size := h.Size()
+	for j, entry := range p.Path {
+		if len(entry.Val) != size {
+			return fmt.Errorf("%w: entry %v has size %v, expected %v", ErrBadHashSize, j, len(entry.Val), size)
+		}
+	}
+	// The sides of the entries follow from the index, a path whose sides are those of another
+	// index would prove the leaf at that index under the index of the proof.
+	j, wrong := 0, -1
+	walkPath(p.LeafIndex, p.TreeSize, func(level, node, sibling int) {
+		if wrong < 0 && p.Path[j].RightOperator != (sibling > node) {
+			wrong = j
+		}
+		j++
+	})
+	if wrong >= 0 {
+		return fmt.Errorf("%w: entry %v is on the wrong side", ErrMalformedProof, wrong)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package merkle
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	sideLeft  = "left"
+	sideRight = "right"
+)
+
+// auditHashJSON is the JSON representation of an AuditHash.
+type auditHashJSON struct {
+	Side string `json:"side"`
+	Hash string `json:"hash"`
+}
+
+// MarshalJSON encodes the audit hash as {"side":"left"|"right","hash":"<lowercase hex>"},
+// where side tells on which side of the concatenation the hash goes.
+func (a AuditHash) MarshalJSON() ([]byte, error) {
+	side := sideLeft
+	if a.RightOperator {
+		side = sideRight
+	}
+	return json.Marshal(auditHashJSON{Side: side, Hash: hexify(a.Val)})
+}
+
+// UnmarshalJSON decodes an audit hash encoded by MarshalJSON.
+// This errors with ErrMalformedProof on unknown sides and on hashes that are not valid hex
+// or whose length is zero or larger than maxHashSize. An AuditHash does not know its hash
+// function, Hasher.UnmarshalPathJSON decodes a path checking the digest size of the Hasher.
+func (a *AuditHash) UnmarshalJSON(data []byte) error {
+	var v auditHashJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var right bool
+	switch v.Side {
+	case sideLeft:
+	case sideRight:
+		right = true
+	default:
+		return fmt.Errorf("%w: unknown side %q", ErrMalformedProof, v.Side)
+	}
+
+	if len(v.Hash) == 0 || len(v.Hash) > 2*maxHashSize {
+		return fmt.Errorf("%w: hash of %v hex characters", ErrMalformedProof, len(v.Hash))
+	}
+	val, err := unhexify(v.Hash)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+
+	a.Val = val
+	a.RightOperator = right
+	return nil
+}
+
+// UnmarshalPathJSON decodes a JSON array of audit hashes encoded by AuditHash.MarshalJSON for the
+// default hash function, see Hasher.UnmarshalPathJSON.
+func UnmarshalPathJSON(data []byte) ([]AuditHash, error) {
+	return defaultHasher.UnmarshalPathJSON(data)
+}
+
+// UnmarshalPathJSON decodes a JSON array of audit hashes encoded by AuditHash.MarshalJSON, such as
+// the path a browser posts, every hash must have the size of the Hasher's digests.
+// This errors with ErrMalformedProof when the array cannot be decoded or an entry does not have
+// the digest size, naming the entry, and with ErrPathTooLong when it has more entries than the
+// Hasher's Limits allow.
+func (h *Hasher) UnmarshalPathJSON(data []byte) ([]AuditHash, error) {
+	var path []AuditHash
+	if err := json.Unmarshal(data, &path); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	if err := h.checkPathLen(len(path)); err != nil {
+		return nil, err
+	}
+	for i, entry := range path {
+		if len(entry.Val) != h.Size() {
+			return nil, fmt.Errorf("%w: entry %v has a hash of %v bytes, expected %v", ErrMalformedProof, i, len(entry.Val), h.Size())
+		}
+	}
+	if path == nil {
+		path = []AuditHash{}
+	}
+	return path, nil
+}
@@ -0,0 +1,45 @@
+package merkle
+
+import (
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalPathJSON(t *testing.T) {
+	path, err := Proof(testItems(5), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalPathJSON(data)
+	if err != nil || !reflect.DeepEqual(got, path) {
+		t.Errorf("UnmarshalPathJSON = %v, %v, want %v", got, err, path)
+	}
+	if got, err := UnmarshalPathJSON([]byte("[]")); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("UnmarshalPathJSON of an empty path = %#v, %v", got, err)
+	}
+	for _, c := range []struct {
+		data string
+		want error
+	}{
+		{`[{"side":"left","hash":"abcd"}]`, ErrMalformedProof},
+		{`[{"side":"up","hash":"` + hexify(path[0].Val) + `"}]`, ErrMalformedProof},
+		{`{}`, ErrMalformedProof},
+	} {
+		if _, err := UnmarshalPathJSON([]byte(c.data)); !errors.Is(err, c.want) {
+			t.Errorf("UnmarshalPathJSON(%s): got %v, want %v", c.data, err, c.want)
+		}
+	}
+	if _, err := NewHasher(WithLimits(Limits{MaxPathLen: 2})).UnmarshalPathJSON(data); !errors.Is(err, ErrPathTooLong) {
+		t.Errorf("UnmarshalPathJSON over the limit: got %v, want ErrPathTooLong", err)
+	}
+	if _, err := NewHasher(WithHash(sha512.New)).UnmarshalPathJSON(data); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("UnmarshalPathJSON of SHA3-256 hashes for SHA-512: got %v, want ErrMalformedProof", err)
+	}
+}
@@ -0,0 +1,202 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// KVTree is a merkle tree over key value pairs sorted by key in increasing byte order, whose
+// proofs are addressed by key. The leaf of a pair is
+//
+//	uint64  length of the key, big endian
+//	key
+//	uint64  length of the value, big endian
+//	value
+//
+// which is injective, hashed as any other leaf. The absence of a key is proven by the pairs
+// just before and after it, as for a SortedTree.
+// A KVTree is not safe for concurrent use.
+type KVTree struct {
+	h      *Hasher
+	keys   [][]byte
+	values [][]byte
+	t      *Tree
+}
+
+// NewKVTree returns an empty KVTree, the options configure the hash function as for NewHasher.
+func NewKVTree(opts ...Option) *KVTree {
+	return NewHasher(opts...).NewKVTree()
+}
+
+// NewKVTree returns an empty KVTree using the Hasher's hash function.
+func (h *Hasher) NewKVTree() *KVTree {
+	return &KVTree{h: h, t: h.NewTree(nil)}
+}
+
+// kvLeaf returns the leaf of a key value pair.
+func kvLeaf(key, value []byte) []byte {
+	leaf := make([]byte, 8+len(key)+8, 16+len(key)+len(value))
+	binary.BigEndian.PutUint64(leaf, uint64(len(key)))
+	copy(leaf[8:], key)
+	binary.BigEndian.PutUint64(leaf[8+len(key):], uint64(len(value)))
+	return append(leaf, value...)
+}
+
+// Root returns the root hash of the tree.
+func (kv *KVTree) Root() []byte {
+	return kv.t.Root()
+}
+
+// Len returns the number of keys of the tree.
+func (kv *KVTree) Len() int {
+	return len(kv.keys)
+}
+
+// search returns the index of the first key that is not smaller than key and whether it is equal to key.
+func (kv *KVTree) search(key []byte) (int, bool) {
+	i := sort.Search(len(kv.keys), func(i int) bool { return bytes.Compare(kv.keys[i], key) >= 0 })
+	return i, i < len(kv.keys) && bytes.Equal(kv.keys[i], key)
+}
+
+// Get returns a copy of the value of key and whether the key is set.
+func (kv *KVTree) Get(key []byte) ([]byte, bool) {
+	i, ok := kv.search(key)
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), kv.values[i]...), true
+}
+
+// Put sets the value of key. Replacing the value of a key or adding a key after every other
+// rehashes O(log n) nodes, adding a key elsewhere moves the leaves after it and rebuilds the tree.
+func (kv *KVTree) Put(key, value []byte) {
+	key, value = append([]byte(nil), key...), append([]byte(nil), value...)
+	i, ok := kv.search(key)
+	switch {
+	case ok:
+		kv.values[i] = value
+		kv.t.Update(i, kvLeaf(key, value))
+	case i == len(kv.keys):
+		kv.keys = append(kv.keys, key)
+		kv.values = append(kv.values, value)
+		kv.t.Append(kvLeaf(key, value))
+	default:
+		kv.keys = append(kv.keys[:i], append([][]byte{key}, kv.keys[i:]...)...)
+		kv.values = append(kv.values[:i], append([][]byte{value}, kv.values[i:]...)...)
+		kv.rebuild()
+	}
+}
+
+// Delete removes key from the tree, reporting whether it was set. The tree is rebuilt.
+func (kv *KVTree) Delete(key []byte) bool {
+	i, ok := kv.search(key)
+	if !ok {
+		return false
+	}
+	kv.keys = append(kv.keys[:i], kv.keys[i+1:]...)
+	kv.values = append(kv.values[:i], kv.values[i+1:]...)
+	kv.rebuild()
+	return true
+}
+
+func (kv *KVTree) rebuild() {
+	leaves := make([][]byte, len(kv.keys))
+	for i := range leaves {
+		leaves[i] = kvLeaf(kv.keys[i], kv.values[i])
+	}
+	kv.t = kv.h.NewTree(leaves)
+}
+
+// KVProof proves that Key is set to Value in a KVTree.
+type KVProof struct {
+	Key   []byte
+	Value []byte
+	InclusionProof
+}
+
+// KVAbsenceProof proves that Key is not set in a KVTree of TreeSize keys with the pairs just before
+// and after it, Left being nil when the key comes before every key and Right being nil when it
+// comes after every key. The proof of the empty tree has no neighbor.
+type KVAbsenceProof struct {
+	Key      []byte
+	TreeSize int
+	Left     *KVProof
+	Right    *KVProof
+}
+
+// ProveKey returns the proof of the value of key.
+// This errors with ErrKeyNotFound when the key is not set.
+func (kv *KVTree) ProveKey(key []byte) (KVProof, error) {
+	i, ok := kv.search(key)
+	if !ok {
+		return KVProof{}, ErrKeyNotFound
+	}
+	return kv.proof(i), nil
+}
+
+// ProveNoKey returns the proof that key is not set.
+// This errors with ErrKeyExists when the key is set.
+func (kv *KVTree) ProveNoKey(key []byte) (KVAbsenceProof, error) {
+	i, ok := kv.search(key)
+	if ok {
+		return KVAbsenceProof{}, fmt.Errorf("%w: key at index %v", ErrKeyExists, i)
+	}
+	p := KVAbsenceProof{Key: append([]byte(nil), key...), TreeSize: len(kv.keys)}
+	if i > 0 {
+		left := kv.proof(i - 1)
+		p.Left = &left
+	}
+	if i < len(kv.keys) {
+		right := kv.proof(i)
+		p.Right = &right
+	}
+	return p, nil
+}
+
+func (kv *KVTree) proof(i int) KVProof {
+	path, _ := kv.t.Proof(i)
+	return KVProof{
+		Key:            append([]byte(nil), kv.keys[i]...),
+		Value:          append([]byte(nil), kv.values[i]...),
+		InclusionProof: InclusionProof{LeafIndex: i, TreeSize: len(kv.keys), Path: path},
+	}
+}
+
+// Verify verifies that the key of the proof is set to its value in the KVTree whose root is root.
+// This errors like InclusionProof.Verify.
+func (p KVProof) Verify(root []byte) error {
+	return defaultHasher.VerifyKV(root, p)
+}
+
+// VerifyKV verifies a KVProof using the Hasher's hash function.
+func (h *Hasher) VerifyKV(root []byte, p KVProof) error {
+	return h.VerifyInclusion(root, kvLeaf(p.Key, p.Value), p.InclusionProof)
+}
+
+// Verify verifies that the key of the proof is not set in the KVTree whose root is root.
+// This errors with ErrKeyExists when a neighbor has the key itself, ErrMalformedProof when the
+// neighbors do not bracket the key or are not adjacent pairs of a tree of the proof's size and
+// otherwise like InclusionProof.Verify.
+func (p KVAbsenceProof) Verify(root []byte) error {
+	return defaultHasher.VerifyNoKey(root, p)
+}
+
+// VerifyNoKey verifies a KVAbsenceProof using the Hasher's hash function.
+func (h *Hasher) VerifyNoKey(root []byte, p KVAbsenceProof) error {
+	a := AbsenceProof{TreeSize: p.TreeSize}
+	if p.Left != nil {
+		a.Left = &Neighbor{Leaf: p.Left.Key, InclusionProof: p.Left.InclusionProof}
+	}
+	if p.Right != nil {
+		a.Right = &Neighbor{Leaf: p.Right.Key, InclusionProof: p.Right.InclusionProof}
+	}
+	// The neighbors are ordered by their keys and included as the leaves of their pairs.
+	return h.checkAbsence(root, p.Key, a, func(n *Neighbor) []byte {
+		if n == a.Left {
+			return kvLeaf(p.Left.Key, p.Left.Value)
+		}
+		return kvLeaf(p.Right.Key, p.Right.Value)
+	})
+}
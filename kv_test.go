@@ -0,0 +1,57 @@
+package merkle
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestKVTree(keys ...string) *KVTree {
+	kv := NewKVTree()
+	for _, k := range keys {
+		kv.Put([]byte(k), []byte("value of "+k))
+	}
+	return kv
+}
+
+func TestKVProofs(t *testing.T) {
+	kv := newTestKVTree("e", "a", "d", "b")
+	root := kv.Root()
+	for _, k := range []string{"a", "b", "d", "e"} {
+		p, err := kv.ProveKey([]byte(k))
+		if err != nil {
+			t.Fatalf("ProveKey(%q): %v", k, err)
+		}
+		if err := p.Verify(root); err != nil {
+			t.Errorf("Verify(%q): %v", k, err)
+		}
+	}
+	for _, k := range []string{"", "c", "f"} {
+		p, err := kv.ProveNoKey([]byte(k))
+		if err != nil {
+			t.Fatalf("ProveNoKey(%q): %v", k, err)
+		}
+		if err := p.Verify(root); err != nil {
+			t.Errorf("Verify absence of %q: %v", k, err)
+		}
+	}
+}
+
+// TestVerifyNoKeyForgedNeighbor relabels the pair of d, at index 2, as the pair at index 1 to
+// prove that b, which is set, is not.
+func TestVerifyNoKeyForgedNeighbor(t *testing.T) {
+	kv := newTestKVTree("a", "b", "d", "e")
+	root := kv.Root()
+	a, err := kv.ProveKey([]byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := kv.ProveKey([]byte("d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.LeafIndex = 1
+	forged := KVAbsenceProof{Key: []byte("b"), TreeSize: 4, Left: &a, Right: &d}
+	if err := forged.Verify(root); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("forged absence of b: got %v, want ErrMalformedProof", err)
+	}
+}
@@ -0,0 +1,106 @@
+package merkle
+
+import "fmt"
+
+// The functions below take leaf hashes instead of items: each input is H(0x00 || item) as
+// returned by LeafHash, it is used as the leaf node of the tree and not hashed again.
+
+// RootFromLeafHashes returns the root hash of the tree whose leaf nodes are leafHashes,
+// which equals Root over the items they were computed from.
+// This errors with ErrInvalidHash when a leaf hash does not have the digest size.
+func RootFromLeafHashes(leafHashes [][]byte) ([]byte, error) {
+	return defaultHasher.RootFromLeafHashes(leafHashes)
+}
+
+// RootFromLeafHashes returns the root hash of the tree whose leaf nodes are leafHashes
+// using the Hasher's hash function.
+func (h *Hasher) RootFromLeafHashes(leafHashes [][]byte) ([]byte, error) {
+	if err := h.checkLeafHashes(leafHashes); err != nil {
+		return nil, err
+	}
+	if len(leafHashes) == 0 {
+		return h.emptyHash(), nil
+	}
+	size := h.Size()
+	buf := make([]byte, len(leafHashes)*size)
+	level := make([][]byte, len(leafHashes))
+	for i, leaf := range leafHashes {
+		level[i] = buf[i*size : (i+1)*size : (i+1)*size]
+		copy(level[i], leaf)
+	}
+	return h.fold(nil, level)
+}
+
+// NewTreeFromLeafHashes returns a tree whose leaf nodes are leafHashes, its proofs are the ones
+// of the tree over the items the leaf hashes were computed from.
+// The options configure the hash function as for NewHasher.
+// This errors with ErrInvalidHash when a leaf hash does not have the digest size.
+func NewTreeFromLeafHashes(leafHashes [][]byte, opts ...Option) (*Tree, error) {
+	return NewHasher(opts...).NewTreeFromLeafHashes(leafHashes)
+}
+
+// NewTreeFromLeafHashes returns a tree whose leaf nodes are leafHashes using the Hasher's hash function.
+func (h *Hasher) NewTreeFromLeafHashes(leafHashes [][]byte) (*Tree, error) {
+	if err := h.checkLeafHashes(leafHashes); err != nil {
+		return nil, err
+	}
+	level := make([][]byte, len(leafHashes))
+	for i, leaf := range leafHashes {
+		level[i] = append([]byte(nil), leaf...)
+	}
+	return h.fromLeafHashes(level), nil
+}
+
+// ProofFromLeafHashes returns the audit path of the leaf at index i of the tree whose leaf nodes are leafHashes.
+// This errors with ErrInvalidHash when a leaf hash does not have the digest size and otherwise like Proof.
+func ProofFromLeafHashes(leafHashes [][]byte, i int) ([]AuditHash, error) {
+	return defaultHasher.ProofFromLeafHashes(leafHashes, i)
+}
+
+// ProofFromLeafHashes returns the audit path of the leaf at index i of the tree whose leaf nodes
+// are leafHashes using the Hasher's hash function.
+func (h *Hasher) ProofFromLeafHashes(leafHashes [][]byte, i int) ([]AuditHash, error) {
+	t, err := h.NewTreeFromLeafHashes(leafHashes)
+	if err != nil {
+		return nil, err
+	}
+	return t.Proof(i)
+}
+
+// VerifyLeafHash verifies that the leaf whose hash is leafHash is included at index in the tree
+// whose root hash is root, like VerifyProof(root, item, index, path) for leafHash = LeafHash(item).
+func VerifyLeafHash(root, leafHash []byte, index int, path []AuditHash) bool {
+	return defaultHasher.VerifyLeafHash(root, leafHash, index, path)
+}
+
+// VerifyLeafHash verifies a leaf hash using the Hasher's hash function.
+// The leaf hash is LeafHashAt(index, item) for a Hasher created WithLeafIndexBinding.
+// A leaf hash that does not have the digest size never verifies.
+func (h *Hasher) VerifyLeafHash(root, leafHash []byte, index int, path []AuditHash) bool {
+	return h.VerifyLeafHashE(root, leafHash, index, path) == nil
+}
+
+// VerifyLeafHashE is VerifyLeafHash returning why a proof does not verify, nil when it does.
+func VerifyLeafHashE(root, leafHash []byte, index int, path []AuditHash) error {
+	return defaultHasher.VerifyLeafHashE(root, leafHash, index, path)
+}
+
+// VerifyLeafHashE is VerifyLeafHash using the Hasher's hash function and returning why a proof
+// does not verify. This errors with ErrBadHashSize when the leaf hash does not have the digest
+// size and otherwise like VerifyE.
+func (h *Hasher) VerifyLeafHashE(root, leafHash []byte, index int, path []AuditHash) error {
+	if len(leafHash) != h.Size() {
+		return fmt.Errorf("%w: leaf hash has size %v, expected %v", ErrBadHashSize, len(leafHash), h.Size())
+	}
+	return h.verifyFrom(root, append([]byte(nil), leafHash...), index, path)
+}
+
+func (h *Hasher) checkLeafHashes(leafHashes [][]byte) error {
+	size := h.Size()
+	for i, leaf := range leafHashes {
+		if len(leaf) != size {
+			return fmt.Errorf("%w: leaf hash %v has size %v, expected %v", ErrInvalidHash, i, len(leaf), size)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// LeafHashReader returns the hash of the leaf whose data is read from r until EOF, as LeafHash
+// does for the data in memory. The data is streamed into the hash function after the leaf prefix,
+// so the leaf hashes of large objects can be given to RootFromLeafHashes in bounded memory.
+// This errors with the error of r, wrapped, when r fails before EOF.
+func LeafHashReader(r io.Reader) ([]byte, error) {
+	return defaultHasher.LeafHashReader(r)
+}
+
+// LeafHashReader returns the hash of the leaf read from r using the Hasher's hash function.
+// A Hasher set WithNodeHasher reads the whole leaf in memory before hashing it.
+// This errors with ErrInvalidLeaf when the Hasher uses LeafLengthPrefixed, whose length is
+// written before the data, and with the error of r, wrapped, when r fails before EOF.
+func (h *Hasher) LeafHashReader(r io.Reader) ([]byte, error) {
+	return h.leafHashReader(r, nil)
+}
+
+// LeafHashReaderAt returns the hash of the leaf at index i read from r, which is LeafHashReader(r)
+// unless the Hasher was created WithLeafIndexBinding.
+// This errors like LeafHashReader.
+func (h *Hasher) LeafHashReaderAt(i int, r io.Reader) ([]byte, error) {
+	if !h.indexedLeaves {
+		return h.leafHashReader(r, nil)
+	}
+	var index [8]byte
+	binary.BigEndian.PutUint64(index[:], uint64(i))
+	return h.leafHashReader(r, index[:])
+}
+
+// leafHashReader hashes the leaf prefix, the index if any and the data read from r.
+func (h *Hasher) leafHashReader(r io.Reader, index []byte) ([]byte, error) {
+	if h.leafEncoding == LeafLengthPrefixed {
+		return nil, fmt.Errorf("%w: the length of a leaf read from a reader is not known", ErrInvalidLeaf)
+	}
+	if h.nodeHasher != nil {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("merkle: reading leaf: %w", err)
+		}
+		return h.nodeHasherLeaf(nil, index, data), nil
+	}
+	d := h.acquireLeaf()
+	defer h.leafPool.Put(d)
+	d.Write(h.leafPrefix)
+	d.Write(index)
+	if _, err := io.Copy(d, r); err != nil {
+		return nil, fmt.Errorf("merkle: reading leaf: %w", err)
+	}
+	return d.Sum(nil), nil
+}
@@ -0,0 +1,32 @@
+package merkle
+
+import "fmt"
+
+// Level returns the node hashes of the tree at level k, counted from the leaves, from left to right.
+// Level 0 holds the leaf hashes and level Depth() the root alone. A node without a sibling is
+// carried up unchanged, so pairing the nodes of a level from the left with NodeHash, carrying
+// the last one of an odd level, and repeating up to a single node yields the root.
+// This errors with ErrEmptyTree when the tree has no items and ErrIndexOutOfBounds when k
+// is not a level of the tree.
+func (t *Tree) Level(k int) ([][]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.levels) == 0 {
+		return nil, ErrEmptyTree
+	}
+	if k < 0 || k >= len(t.levels) {
+		return nil, fmt.Errorf("%w: level %v, tree has %v levels", ErrIndexOutOfBounds, k, len(t.levels))
+	}
+	return append([][]byte(nil), t.levels[k]...), nil
+}
+
+// Level returns the node hashes at level k of the tree over items, as Tree.Level does.
+func Level(items [][]byte, k int) ([][]byte, error) {
+	return defaultHasher.Level(items, k)
+}
+
+// Level returns the node hashes at level k of the tree over items using the Hasher's hash function.
+func (h *Hasher) Level(items [][]byte, k int) ([][]byte, error) {
+	return h.NewTree(items).Level(k)
+}
@@ -0,0 +1,52 @@
+package merkle
+
+import "fmt"
+
+// Limits bounds the work the verifiers and decoders do on proofs from untrusted sources, so that
+// an absurd proof is rejected before it is hashed or allocated. A field that is zero or negative
+// removes its limit.
+type Limits struct {
+	MaxPathLen  int // entries of an audit path
+	MaxLeafSize int // bytes of a leaf being verified or decoded
+	MaxTreeSize int // tree size declared by a proof
+}
+
+// DefaultLimits are the limits of a Hasher created without WithLimits. No audit path of a tree
+// of less than 2^64 leaves is longer than 64 entries, which is the largest tree an int can size.
+var DefaultLimits = Limits{
+	MaxPathLen:  64,
+	MaxLeafSize: 64 << 20,
+	MaxTreeSize: maxInt,
+}
+
+// WithLimits replaces DefaultLimits for the proofs the Hasher verifies and decodes, for example
+// to accept leaves larger than 64 MiB or to reject proofs of trees larger than a log can grow.
+func WithLimits(l Limits) Option {
+	return func(h *Hasher) {
+		h.limits = l
+	}
+}
+
+// checkPathLen returns an ErrPathTooLong error when a path of n entries exceeds the Hasher's limits.
+func (h *Hasher) checkPathLen(n int) error {
+	if max := h.limits.MaxPathLen; max > 0 && n > max {
+		return fmt.Errorf("%w: %v entries, at most %v", ErrPathTooLong, n, max)
+	}
+	return nil
+}
+
+// checkLeafSize returns an ErrLeafTooLarge error when a leaf of n bytes exceeds the Hasher's limits.
+func (h *Hasher) checkLeafSize(n int) error {
+	if max := h.limits.MaxLeafSize; max > 0 && n > max {
+		return fmt.Errorf("%w: %v bytes, at most %v", ErrLeafTooLarge, n, max)
+	}
+	return nil
+}
+
+// checkTreeSize returns an ErrTreeTooLarge error when a tree of n leaves exceeds the Hasher's limits.
+func (h *Hasher) checkTreeSize(n int) error {
+	if max := h.limits.MaxTreeSize; max > 0 && n > max {
+		return fmt.Errorf("%w: %v leaves, at most %v", ErrTreeTooLarge, n, max)
+	}
+	return nil
+}
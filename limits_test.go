@@ -0,0 +1,103 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHasherLimitsHex(t *testing.T) {
+	entries := make([]string, 80)
+	for i := range entries {
+		entries[i] = "L:" + strings.Repeat("ab", 32)
+	}
+	if _, err := DecodeProofHex(entries); !errors.Is(err, ErrPathTooLong) {
+		t.Errorf("DecodeProofHex of 80 entries: got %v, want ErrPathTooLong", err)
+	}
+	h := NewHasher(WithLimits(Limits{MaxPathLen: 100}))
+	if path, err := h.DecodeProofHex(entries); err != nil || len(path) != 80 {
+		t.Errorf("Hasher.DecodeProofHex of 80 entries: %v", err)
+	}
+	if path, err := h.ParsePath(strings.Join(entries, ";")); err != nil || len(path) != 80 {
+		t.Errorf("Hasher.ParsePath of 80 entries: %v", err)
+	}
+	if _, err := h.DecodeProofHex([]string{"R:abcd"}); !errors.Is(err, ErrMalformedProof) {
+		t.Errorf("Hasher.DecodeProofHex of a short hash: got %v, want ErrMalformedProof", err)
+	}
+	small := NewHasher(WithLimits(Limits{MaxPathLen: 2}))
+	if _, err := small.ParsePath(strings.Join(entries[:3], ";")); !errors.Is(err, ErrPathTooLong) {
+		t.Errorf("ParsePath over the limit: got %v, want ErrPathTooLong", err)
+	}
+}
+
+func TestHasherLimitsBundle(t *testing.T) {
+	items := [][]byte{bytes.Repeat([]byte("x"), 100), []byte("b"), []byte("c")}
+	b, err := CreateBundle(items, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	small := NewHasher(WithLimits(Limits{MaxLeafSize: 64}))
+	if _, err := small.UnmarshalBundle(data); !errors.Is(err, ErrLeafTooLarge) {
+		t.Errorf("UnmarshalBundle over the limit: got %v, want ErrLeafTooLarge", err)
+	}
+	got, err := defaultHasher.UnmarshalBundle(data)
+	if err != nil || !reflect.DeepEqual(got, b) {
+		t.Errorf("UnmarshalBundle = %+v, %v, want %+v", got, err, b)
+	}
+	if _, err := NewHasher(WithSHA256()).UnmarshalBundle(data); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("UnmarshalBundle of another hash: got %v, want ErrHashMismatch", err)
+	}
+}
+
+func FuzzParsePath(f *testing.F) {
+	path, _ := Proof([][]byte{[]byte("a"), []byte("b"), []byte("c")}, 2)
+	f.Add(FormatPath(path))
+	f.Add("L:00;R:")
+	f.Add(";;")
+	h := NewHasher(WithLimits(Limits{MaxPathLen: 4}))
+	f.Fuzz(func(t *testing.T, s string) {
+		path, err := h.ParsePath(s)
+		if err != nil {
+			return
+		}
+		if len(path) > 4 {
+			t.Fatalf("ParsePath returned %v entries over a limit of 4", len(path))
+		}
+		again, err := h.ParsePath(FormatPath(path))
+		if err != nil || !reflect.DeepEqual(again, path) {
+			t.Fatalf("ParsePath(FormatPath(%v)) = %v, %v", path, again, err)
+		}
+	})
+}
+
+func FuzzUnmarshalBundle(f *testing.F) {
+	b, _ := CreateBundle([][]byte{[]byte("a"), []byte("b"), []byte("c")}, 1)
+	data, _ := b.MarshalBinary()
+	f.Add(data)
+	f.Add(data[:len(data)-1])
+	f.Add([]byte("MRKL"))
+	h := NewHasher(WithLimits(Limits{MaxPathLen: 8, MaxLeafSize: 16, MaxTreeSize: 1 << 10}))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		b, err := h.UnmarshalBundle(data)
+		if err != nil {
+			return
+		}
+		if len(b.Path) > 8 || len(b.Leaf) > 16 || b.TreeSize > 1<<10 {
+			t.Fatalf("UnmarshalBundle returned a bundle over the limits: %+v", b)
+		}
+		again, err := b.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary of a decoded bundle: %v", err)
+		}
+		c, err := h.UnmarshalBundle(again)
+		if err != nil || !reflect.DeepEqual(b, c) {
+			t.Fatalf("bundle does not round trip: %+v, %+v, %v", b, c, err)
+		}
+	})
+}
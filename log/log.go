@@ -0,0 +1,109 @@
+// Package log implements an append-only, tamper-evident log on top of the merkle package: entries
+// are appended to a Storage and to a merkle tree, whose heads, inclusion proofs and consistency
+// proofs the log serves. Heads can be signed with merkle.SignTreeHead.
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	merkle "github.com/actuallyachraf/go-merkle"
+)
+
+// ErrStorage is returned when the storage of a log fails or disagrees with the log.
+var ErrStorage = errors.New("log: storage failure")
+
+// Log is an append-only merkle log, safe for concurrent use.
+// An entry is added to the tree only once its storage returned, so every head returned by the
+// log is the head of a prefix of the stored entries, which a log reopened on the same storage
+// can prove consistent with its later heads.
+type Log struct {
+	mu sync.Mutex
+	s  Storage
+	t  *merkle.Tree
+}
+
+// New returns an empty Log kept in a MemoryStorage, the options configure the hash function as
+// for merkle.NewHasher.
+func New(opts ...merkle.Option) *Log {
+	return &Log{s: NewMemoryStorage(), t: merkle.NewTree(nil, opts...)}
+}
+
+// Open returns the Log of the entries of s, rehashing them, the options configure the hash
+// function as for merkle.NewHasher and must be the ones the log was built with.
+// This errors with ErrStorage wrapping the error of the storage when the entries cannot be read.
+func Open(s Storage, opts ...merkle.Option) (*Log, error) {
+	n, err := s.Len()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	t := merkle.NewTree(nil, opts...)
+	for i := 0; i < n; i++ {
+		entry, err := s.Entry(i)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %v: %v", ErrStorage, i, err)
+		}
+		t.Append(entry)
+	}
+	return &Log{s: s, t: t}, nil
+}
+
+// Append stores entry at the end of the log and returns its index and the head of the log
+// including it. Appends are serialized.
+// This errors with ErrStorage wrapping the error of the storage when the entry cannot be
+// stored, in which case the log is unchanged.
+func (l *Log) Append(entry []byte) (int, merkle.TreeHead, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.s.Append(entry); err != nil {
+		return 0, merkle.TreeHead{}, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	i := l.t.Append(entry)
+	return i, l.t.TreeHead(), nil
+}
+
+// Size returns the number of entries of the log.
+func (l *Log) Size() int {
+	return l.t.LeafCount()
+}
+
+// Head returns the current head of the log.
+func (l *Log) Head() merkle.TreeHead {
+	return l.t.TreeHead()
+}
+
+// Root returns the root hash of the log when it had size entries.
+// This errors with merkle.ErrIndexOutOfBounds when size is negative or larger than the log.
+func (l *Log) Root(size int) ([]byte, error) {
+	return l.t.RootAtSize(size)
+}
+
+// Entry returns the entry at index i.
+// This errors with merkle.ErrIndexOutOfBounds when i is not an index of the log and with
+// ErrStorage wrapping the error of the storage when the entry cannot be read.
+func (l *Log) Entry(i int) ([]byte, error) {
+	if n := l.Size(); i < 0 || i >= n {
+		return nil, fmt.Errorf("%w: index %v, log has %v entries", merkle.ErrIndexOutOfBounds, i, n)
+	}
+	entry, err := l.s.Entry(i)
+	if err != nil {
+		return nil, fmt.Errorf("%w: entry %v: %v", ErrStorage, i, err)
+	}
+	return entry, nil
+}
+
+// InclusionProof returns the proof that the entry at index is in the log of atSize entries,
+// which verifies against the root of a head of that size.
+// This errors like merkle.Tree.ProofAtSize.
+func (l *Log) InclusionProof(index, atSize int) (merkle.InclusionProof, error) {
+	return l.t.ProofAtSize(index, atSize)
+}
+
+// ConsistencyProof returns the proof that the log of oldSize entries is a prefix of the log of
+// newSize entries, which verifies with merkle.VerifyConsistency against the roots of their heads.
+// This errors like merkle.Tree.ConsistencyProof.
+func (l *Log) ConsistencyProof(oldSize, newSize int) ([]merkle.AuditHash, error) {
+	return l.t.ConsistencyProof(oldSize, newSize)
+}
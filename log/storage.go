@@ -0,0 +1,56 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+
+	merkle "github.com/actuallyachraf/go-merkle"
+)
+
+// Storage persists the entries of a Log in order.
+type Storage interface {
+	// Len returns the number of stored entries.
+	Len() (int, error)
+	// Entry returns the entry at index i.
+	Entry(i int) ([]byte, error)
+	// Append stores entry after the stored entries. It must be atomic: when it returns an error
+	// the entry is not stored, and once it returns nil the entry is stored durably.
+	Append(entry []byte) error
+}
+
+// MemoryStorage is a Storage keeping the entries in memory, safe for concurrent use.
+type MemoryStorage struct {
+	mu      sync.RWMutex
+	entries [][]byte
+}
+
+// NewMemoryStorage returns an empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{}
+}
+
+// Len returns the number of stored entries.
+func (s *MemoryStorage) Len() (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries), nil
+}
+
+// Entry returns a copy of the entry at index i.
+// This errors with merkle.ErrIndexOutOfBounds when there is no such entry.
+func (s *MemoryStorage) Entry(i int) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i < 0 || i >= len(s.entries) {
+		return nil, fmt.Errorf("%w: index %v, storage has %v entries", merkle.ErrIndexOutOfBounds, i, len(s.entries))
+	}
+	return append([]byte(nil), s.entries[i]...), nil
+}
+
+// Append stores a copy of entry.
+func (s *MemoryStorage) Append(entry []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, append([]byte(nil), entry...))
+	return nil
+}
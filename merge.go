@@ -0,0 +1,93 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Merge returns the tree whose leaves are the leaves of a followed by the leaves of b.
+// Subtrees of a are reused as they are; subtrees of b are reused at the levels where the
+// number of leaves of a keeps them aligned, only the nodes across the seam are hashed.
+// The trees are not modified.
+// This errors with ErrHashMismatch when the trees do not hash the same way or bind the index of
+// their leaves into the leaf hashes, which would change in the merged tree.
+func Merge(a, b *Tree) (*Tree, error) {
+	defer rLockPair(a, b)()
+	if !a.h.sameHashing(b.h) {
+		return nil, fmt.Errorf("%w: merging trees built with different hashers", ErrHashMismatch)
+	}
+	if a.h.indexedLeaves && b.leafCount() > 0 {
+		return nil, fmt.Errorf("%w: the leaf hashes of the second tree bind their indices in it", ErrHashMismatch)
+	}
+
+	na, nb := a.leafCount(), b.leafCount()
+	level := make([][]byte, 0, na+nb)
+	if na > 0 {
+		level = append(level, a.levels[0]...)
+	}
+	if nb > 0 {
+		level = append(level, b.levels[0]...)
+	}
+	return a.h.fromLeafHashesReusing(level, func(k, j int) []byte {
+		lo, hi := j<<uint(k), (j+1)<<uint(k)
+		switch {
+		case hi <= na:
+			return a.levels[k][j]
+		case lo >= na && hi <= na+nb && na%(1<<uint(k)) == 0:
+			return b.levels[k][(lo-na)>>uint(k)]
+		}
+		return nil
+	}), nil
+}
+
+// sameHashing reports whether two Hashers produce the same hashes.
+// Hashers set with WithHash are only known to agree when they are the same Hasher.
+func (h *Hasher) sameHashing(o *Hasher) bool {
+	if h == o {
+		return true
+	}
+	return h.id != HashCustom && h.id == o.id && h.sortPairs == o.sortPairs && h.indexedLeaves == o.indexedLeaves && h.leafEncoding == o.leafEncoding &&
+		bytes.Equal(h.leafPrefix, o.leafPrefix) && bytes.Equal(h.interiorPrefix, o.interiorPrefix)
+}
+
+// RemapProof rewrites an inclusion proof from a tree whose leaves are at the given offset in t,
+// such as the second tree given to Merge at offset a.LeafCount(), into the inclusion proof of the
+// same leaf in t. Entries of p whose subtree is still aligned in t are kept, the others are read from t.
+// This errors with ErrIndexOutOfBounds when the leaves of p do not fit in t at offset and with
+// ErrBadPathLength when the path of p does not have the length implied by its index and size.
+func (t *Tree) RemapProof(p InclusionProof, offset int) (InclusionProof, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	n := t.leafCount()
+	if offset < 0 || p.TreeSize < 0 || offset+p.TreeSize > n {
+		return InclusionProof{}, fmt.Errorf("%w: %v items at offset %v, tree has %v items", ErrIndexOutOfBounds, p.TreeSize, offset, n)
+	}
+	if p.LeafIndex < 0 || p.LeafIndex >= p.TreeSize {
+		return InclusionProof{}, indexError(p.LeafIndex, p.TreeSize)
+	}
+	if want := ProofLen(p.LeafIndex, p.TreeSize); len(p.Path) != want {
+		return InclusionProof{}, fmt.Errorf("%w: %v entries for index %v of %v, expected %v", ErrBadPathLength, len(p.Path), p.LeafIndex, p.TreeSize, want)
+	}
+
+	kept := make(map[int]AuditHash, len(p.Path))
+	j := 0
+	walkPath(p.LeafIndex, p.TreeSize, func(level, node, sibling int) {
+		kept[level] = p.Path[j]
+		j++
+	})
+
+	index := offset + p.LeafIndex
+	path := make([]AuditHash, 0, ProofLen(index, n))
+	walkPath(index, n, func(level, node, sibling int) {
+		// The sibling is the same subtree in both trees when it is complete within the leaves of p
+		// and the offset keeps the pair it belongs to aligned.
+		lo, hi := sibling<<uint(level), (sibling+1)<<uint(level)
+		if !t.h.indexedLeaves && offset%(2<<uint(level)) == 0 && lo >= offset && hi <= offset+p.TreeSize {
+			path = append(path, kept[level])
+			return
+		}
+		path = append(path, AuditHash{t.levels[level][sibling], sibling > node})
+	})
+	return InclusionProof{LeafIndex: index, TreeSize: n, Path: path}, nil
+}
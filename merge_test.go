@@ -0,0 +1,87 @@
+package merkle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestMergeSeams merges trees of every size up to 9 on each side of the seam, most of which are
+// not powers of two, and checks the merged tree against the one built over all the items.
+func TestMergeSeams(t *testing.T) {
+	items := testItems(18)
+	for na := 0; na <= 9; na++ {
+		for nb := 0; nb <= 9; nb++ {
+			all := items[:na+nb]
+			m, err := Merge(NewTree(items[:na]), NewTree(items[na:na+nb]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalDigest(m.Root(), Root(all)) {
+				t.Fatalf("merging %v and %v items: wrong root", na, nb)
+			}
+			for i := range all {
+				got, err := m.Proof(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, err := Prove(all, i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got) != len(want.Path) {
+					t.Fatalf("merging %v and %v items: proof of %v has %v entries, want %v", na, nb, i, len(got), len(want.Path))
+				}
+				for j := range got {
+					if !equalDigest(got[j].Val, want.Path[j].Val) || got[j].RightOperator != want.Path[j].RightOperator {
+						t.Fatalf("merging %v and %v items: entry %v of the proof of %v differs", na, nb, j, i)
+					}
+				}
+			}
+		}
+	}
+
+	a := NewTree(items[:5])
+	m, err := Merge(a, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalDigest(m.Root(), Root(append(append([][]byte(nil), items[:5]...), items[:5]...))) {
+		t.Fatal("merging a tree with itself: wrong root")
+	}
+}
+
+func TestMergeLockOrder(t *testing.T) {
+	testLockOrder(t, func(a, b *Tree) {
+		if _, err := Merge(a, b); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// testLockOrder checks that fn, given the trees in either order, read locks the tree at the lower
+// address first: while that tree is write locked, fn must block without holding the other one,
+// or two calls given the trees in opposite orders deadlock against queued writers.
+func testLockOrder(t *testing.T, fn func(a, b *Tree)) {
+	x, y := NewTree(testItems(3)), NewTree(testItems(5))
+	if reflect.ValueOf(x).Pointer() > reflect.ValueOf(y).Pointer() {
+		x, y = y, x
+	}
+	for _, args := range [][2]*Tree{{x, y}, {y, x}} {
+		x.mu.Lock()
+		done := make(chan struct{})
+		go func(a, b *Tree) {
+			defer close(done)
+			fn(a, b)
+		}(args[0], args[1])
+		time.Sleep(10 * time.Millisecond)
+		if !y.mu.TryLock() {
+			x.mu.Unlock()
+			<-done
+			t.Fatal("the tree at the higher address is read locked while waiting for the other one")
+		}
+		y.mu.Unlock()
+		x.mu.Unlock()
+		<-done
+	}
+}
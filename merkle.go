@@ -1,20 +1,36 @@
 package merkle
 
 import (
-	"bytes"
+	"context"
+	"crypto/subtle"
 	"encoding/hex"
-	"errors"
-	"math"
+	"fmt"
+	"math/bits"
+)
 
-	"golang.org/x/crypto/sha3"
+// Domain separation prefixes written before the data of a leaf and the children of
+// an interior node, so that a leaf can never be mistaken for an interior node.
+const (
+	LeafPrefix     byte = 0x00
+	InteriorPrefix byte = 0x01
 )
 
 var (
-	leafPrefix      = []byte{0x00}
-	interiorPrefix  = []byte{0x01}
-	emptyStringHash = sha3.Sum256([]byte{})
+	leafPrefix     = []byte{LeafPrefix}
+	interiorPrefix = []byte{InteriorPrefix}
 )
 
+// LeafHash returns the hash of a leaf, H(0x00 || data).
+// This is the hash every item is turned into before building the tree.
+func LeafHash(data []byte) []byte {
+	return defaultHasher.LeafHash(data)
+}
+
+// NodeHash returns the hash of an interior node, H(0x01 || left || right).
+func NodeHash(left, right []byte) []byte {
+	return defaultHasher.NodeHash(left, right)
+}
+
 // AuditHash stores the hash value and denotes which side of the concatenation
 // operation it should be on.
 // For example, if we have a hashed item A and an audit hash {Val: B, RightOperator: false},
@@ -25,90 +41,122 @@ type AuditHash struct {
 }
 
 // Proof returns the proofs required to validate an item at index i, not including the original item i.
-// This errors when the requested index is out of bounds.
+// This errors with ErrEmptyTree when there are no items and ErrIndexOutOfBounds
+// when the requested index is out of bounds.
 func Proof(items [][]byte, i int) ([]AuditHash, error) {
-	if i < 0 || i >= len(items) {
-		return nil, errors.New("index %v is out of bounds")
-	}
-	if len(items) == 1 {
-		return []AuditHash{}, nil
-	}
+	return defaultHasher.Proof(items, i)
+}
 
-	k := prevPowerOfTwo(len(items))
-	recurse := items[:k]
-	aggregate := items[k:]
-	rightOperator := true
-	if i >= k {
-		i = i - k
-		recurse, aggregate = aggregate, recurse
-		rightOperator = false
-	}
-	res, err := Proof(recurse, i)
-	if err != nil {
-		return nil, err
-	}
-	res = append(res, AuditHash{Root(aggregate), rightOperator})
-	return res, nil
+// Proof returns the proofs required to validate an item at index i using the Hasher's hash function.
+// The items are hashed once into a Tree and the audit path is read from its nodes.
+// This errors with ErrEmptyTree when there are no items and ErrIndexOutOfBounds
+// when the requested index is out of bounds.
+func (h *Hasher) Proof(items [][]byte, i int) ([]AuditHash, error) {
+	return h.NewTree(items).Proof(i)
 }
 
 // Root creates a merkle tree from a slice of byte slices
 // and returns the root hash of the tree.
 func Root(items [][]byte) []byte {
-	switch len(items) {
-	case 0:
-		return emptyStringHash[:]
+	return defaultHasher.Root(items)
+}
 
-	case 1:
-		h := sha3.New256()
+// Root creates a merkle tree from a slice of byte slices using the Hasher's hash function
+// and returns the root hash of the tree.
+//
+// The tree is built bottom-up one level at a time in a single buffer: nodes are paired
+// from the left and the last node of a level with an odd number of nodes is carried up
+// unchanged, which produces the same tree as splitting the items at prevPowerOfTwo.
+func (h *Hasher) Root(items [][]byte) []byte {
+	return h.rootAt(0, items)
+}
 
-		h.Write(leafPrefix)
-		h.Write(items[0])
-		root := h.Sum(nil)
-		return root
+// rootAt returns the root of the subtree over items whose first leaf is at index offset of its tree.
+func (h *Hasher) rootAt(offset int, items [][]byte) []byte {
+	root, _ := h.root(context.Background(), offset, items)
+	return root
+}
 
-	default:
-		k := prevPowerOfTwo(len(items))
-		left := Root(items[:k])
-		right := Root(items[k:])
+// root is rootAt returning ctx.Err() and no root as soon as it sees ctx is done.
+func (h *Hasher) root(ctx context.Context, offset int, items [][]byte) ([]byte, error) {
+	if len(items) == 0 {
+		return h.emptyHash(), nil
+	}
 
-		h := sha3.New256()
-		h.Write(interiorPrefix)
-		h.Write(left[:])
-		h.Write(right[:])
+	return h.rootCounted(h.newProgress(ctx, 2*len(items)-1), offset, items)
+}
 
-		root := h.Sum(nil)
-		return root
+// rootCounted is root over at least one item counting its hashes with p.
+func (h *Hasher) rootCounted(p *progress, offset int, items [][]byte) ([]byte, error) {
+	// Every digest lives in one buffer, a parent is written over the storage of its left child
+	// which is not read again once the parent is computed.
+	size := h.Size()
+	buf := make([]byte, len(items)*size)
+	level := make([][]byte, len(items))
+	for i, item := range items {
+		level[i] = h.leafHashAt(buf[i*size:i*size:(i+1)*size], offset+i, item)
+		if err := p.step(); err != nil {
+			return nil, err
+		}
 	}
+	return h.fold(p, level)
+}
+
+// fold hashes a level of digests up to the root, overwriting the digests, and returns a copy of the root.
+// Each hash is counted by p, a nil p counting the hashes of the fold alone.
+func (h *Hasher) fold(p *progress, level [][]byte) ([]byte, error) {
+	if p == nil {
+		p = h.newProgress(context.Background(), len(level)-1)
+	}
+	size := h.Size()
+	for n := len(level); n > 1; n = (n + 1) / 2 {
+		for i := 0; i < n/2; i++ {
+			left := level[2*i]
+			level[i] = h.nodeHashTo(left[:0:size], left, level[2*i+1])
+			if err := p.step(); err != nil {
+				return nil, err
+			}
+		}
+		if n%2 == 1 {
+			level[n/2] = level[n-1]
+		}
+	}
+	return append([]byte{}, level[0]...), nil
 }
 
 // prevPowerOfTwo returns the largest power of two that is smaller than a given number.
 // In other words, for some input n, the prevPowerOfTwo k is a power of two such that
 // k < n <= 2k. This is a helper function used during the calculation of a merkle tree.
+// There is no such k for n <= 1, which has no split, so 0 is returned.
 func prevPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 0
+	}
+
 	// If the number is a power of two, divide it by 2 and return.
 	if n&(n-1) == 0 {
 		return n / 2
 	}
 
-	// Otherwise, find the previous PoT.
-	exponent := uint(math.Log2(float64(n)))
-	return 1 << exponent // 2^exponent
+	// Otherwise, find the previous PoT from the position of the highest set bit.
+	return 1 << (bits.Len(uint(n)) - 1)
 }
 
-func concat(a []byte, b []byte) []byte {
-	return append(a, b...)
-}
-func hash(a []byte) [32]byte {
-	h := sha3.Sum256(a)
-	return h
+// equalDigest reports whether two digests are equal in time independent of their contents,
+// so that a verifier does not leak how close a forged proof came to the expected root.
+// Every digest comparison of the package goes through it.
+func equalDigest(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
 }
+
 func hexify(a []byte) string {
 	return hex.EncodeToString(a)
 }
 
-func unhexify(s string) []byte {
-	d, _ := hex.DecodeString(s)
-	return d
+// unhexify decodes hex, failing like hex.DecodeString on odd lengths and characters that are not hex
+// rather than returning what could be decoded. Every hex input of the package goes through it.
+func unhexify(s string) ([]byte, error) {
+	return hex.DecodeString(s)
 }
 
 /*
@@ -143,22 +191,148 @@ func unhexify(s string) []byte {
 // Verify takes the hash of an item and an audit path
 // and verifies whether a proof is correct.
 func Verify(items [][]byte, index int, auditpath []AuditHash) bool {
+	return defaultHasher.Verify(items, index, auditpath)
+}
+
+// Verify takes the hash of an item and an audit path
+// and verifies whether a proof is correct using the Hasher's hash function.
+// An index that is not an index of the items, in particular any index of an empty tree, never verifies,
+// and neither does a path whose length is not the one of the index in a tree of len(items) items.
+func (h *Hasher) Verify(items [][]byte, index int, auditpath []AuditHash) bool {
+	if index < 0 || index >= len(items) || len(auditpath) != ProofLen(index, len(items)) {
+		return false
+	}
+	return h.VerifyProof(h.Root(items), items[index], index, auditpath)
+}
+
+// VerifyProof verifies that leaf is included at index in the tree whose root hash is root,
+// using only the audit path returned by Proof.
+func VerifyProof(root []byte, leaf []byte, index int, path []AuditHash) bool {
+	return defaultHasher.VerifyProof(root, leaf, index, path)
+}
+
+// VerifyProof verifies that leaf is included at index in the tree whose root hash is root
+// using the Hasher's hash function.
+// Roots or audit hashes whose length differs from the digest size never verify, so a proof
+// built with a different hash function is rejected.
+// The empty tree contains no leaf, so its root never verifies, and the empty path of a single
+// leaf tree only verifies at index 0. Paths and leaves exceeding the Hasher's Limits never verify.
+//
+// The running digest lives in a pooled buffer and every node is hashed into it from a pooled hash
+// state, so in steady state a call makes no heap allocation for the hash functions of the package.
+func (h *Hasher) VerifyProof(root []byte, leaf []byte, index int, path []AuditHash) bool {
+	return h.VerifyE(root, leaf, index, path) == nil
+}
+
+// VerifyE is VerifyProof returning why a proof does not verify, nil when it does.
+func VerifyE(root []byte, leaf []byte, index int, path []AuditHash) error {
+	return defaultHasher.VerifyE(root, leaf, index, path)
+}
+
+// VerifyE is VerifyProof using the Hasher's hash function and returning why a proof does not verify.
+// Everything but the final comparison is checked before hashing anything.
+// This errors with ErrIndexOutOfBounds for a negative index, ErrBadPathLength for an empty path
+// at an index other than 0, ErrBadHashSize when the root or an entry does not have the digest size,
+// ErrPathTooLong or ErrLeafTooLarge when the proof exceeds the Hasher's Limits and ErrRootMismatch
+// when the path does not lead to root or root is the one of the empty tree. The errors name the
+// index or entry at fault. It only allocates to report an error.
+func (h *Hasher) VerifyE(root []byte, leaf []byte, index int, path []AuditHash) error {
+	if err := h.checkLeafSize(len(leaf)); err != nil {
+		return err
+	}
+	if err := h.checkProof(root, index, path); err != nil {
+		return err
+	}
+	b := h.scratch()
+	err := h.matchRoot(root, h.foldPath(h.leafHashAt((*b)[:0], index, leaf), path))
+	h.release(b)
+	return err
+}
 
-	h := hash(concat(leafPrefix, items[index]))
-	for _, proofs := range auditpath {
+// ComputeRootFromPath returns the root reached by folding the audit path of leaf at index,
+// which is the root of the tree the proof was generated for when it is valid, for example to look it
+// up among known roots. For d4 of the tree drawn above and its path [f, j, k], the folds go through
+// e, i and l before reaching the root.
+// This errors like VerifyE, except that there is no root to compare with.
+func ComputeRootFromPath(leaf []byte, index int, path []AuditHash) ([]byte, error) {
+	return defaultHasher.ComputeRootFromPath(leaf, index, path)
+}
+
+// ComputeRootFromPath returns the root reached by folding the audit path of leaf at index using the Hasher's hash function.
+func (h *Hasher) ComputeRootFromPath(leaf []byte, index int, path []AuditHash) ([]byte, error) {
+	if err := h.checkLeafSize(len(leaf)); err != nil {
+		return nil, err
+	}
+	if err := h.checkPath(index, path); err != nil {
+		return nil, err
+	}
+	return h.foldPath(h.leafHashAt(make([]byte, 0, h.Size()), index, leaf), path), nil
+}
+
+// checkPath checks the index and the entries of an audit path, so that a malformed proof is
+// rejected before its leaf is hashed.
+func (h *Hasher) checkPath(index int, path []AuditHash) error {
+	if index < 0 {
+		return fmt.Errorf("%w: negative index %v", ErrIndexOutOfBounds, index)
+	}
+	if len(path) == 0 && index != 0 {
+		return fmt.Errorf("%w: empty path for index %v, only a single leaf tree has one", ErrBadPathLength, index)
+	}
+	if err := h.checkPathLen(len(path)); err != nil {
+		return err
+	}
+	size := h.Size()
+	for j, entry := range path {
+		if len(entry.Val) != size {
+			return fmt.Errorf("%w: entry %v has size %v, expected %v", ErrBadHashSize, j, len(entry.Val), size)
+		}
+	}
+	return nil
+}
+
+// checkProof is checkPath also checking the root a proof is verified against.
+func (h *Hasher) checkProof(root []byte, index int, path []AuditHash) error {
+	if err := h.checkPath(index, path); err != nil {
+		return err
+	}
+	if len(root) != h.Size() {
+		return fmt.Errorf("%w: root has size %v, expected %v", ErrBadHashSize, len(root), h.Size())
+	}
+	if equalDigest(root, h.empty) {
+		return fmt.Errorf("%w: the empty tree includes no leaf", ErrRootMismatch)
+	}
+	return nil
+}
+
+// verifyFrom checks an audit path starting from the leaf hash d, which it overwrites.
+func (h *Hasher) verifyFrom(root, d []byte, index int, path []AuditHash) error {
+	if err := h.checkProof(root, index, path); err != nil {
+		return err
+	}
+	return h.matchRoot(root, h.foldPath(d, path))
+}
+
+// foldPath folds the entries of a checked audit path into the node d, which it overwrites, and returns the node reached.
+func (h *Hasher) foldPath(d []byte, path []AuditHash) []byte {
+	// The running digest is rehashed in place, each node is written to the hash state before being overwritten.
+	for _, proofs := range path {
 
 		proof := proofs.Val
 		isRight := proofs.RightOperator
 
 		if isRight {
-			concatRight := concat(h[:], proof)
-			h = hash(concat(interiorPrefix, concatRight))
+			d = h.nodeHashTo(d[:0], d, proof)
 		} else {
-			concatLeft := concat(proof, h[:])
-			h = hash(concat(interiorPrefix, concatLeft))
+			d = h.nodeHashTo(d[:0], proof, d)
 		}
 
 	}
+	return d
+}
 
-	return bytes.Equal(Root(items), h[:])
+func (h *Hasher) matchRoot(root, d []byte) error {
+	if !equalDigest(root, d) {
+		return ErrRootMismatch
+	}
+	return nil
 }
@@ -0,0 +1,69 @@
+package merkle
+
+import "testing"
+
+// depth20Proof returns the inclusion proof of a leaf in a tree of 2^20 items and its root,
+// drawing the entries rather than hashing the whole tree.
+func depth20Proof(h *Hasher) (InclusionProof, []byte, []byte) {
+	const depth, index = 20, 0x5a5a5
+	leaf := []byte("leaf")
+	p := InclusionProof{LeafIndex: index, TreeSize: 1 << depth}
+	for level := 0; level < depth; level++ {
+		p.Path = append(p.Path, AuditHash{
+			Val:           h.LeafHash([]byte{byte(level)}),
+			RightOperator: index>>uint(level)&1 == 0,
+		})
+	}
+	root, err := h.ComputeRootFromPath(leaf, index, p.Path)
+	if err != nil {
+		panic(err)
+	}
+	return p, root, leaf
+}
+
+var allocHashers = []struct {
+	name string
+	opts []Option
+}{
+	{"SHA3", nil},
+	{"SHA256", []Option{WithSHA256()}},
+	{"Keccak256", []Option{WithKeccak256()}},
+	{"BLAKE3", []Option{WithBLAKE3()}},
+	{"CSHAKE256", []Option{WithCSHAKE256()}},
+	{"LeafIndexBinding", []Option{WithLeafIndexBinding()}},
+	{"LengthPrefixed", []Option{WithLeafIndexBinding(), WithLeafEncoding(LeafLengthPrefixed)}},
+}
+
+func TestVerifyNoAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+	for _, c := range allocHashers {
+		h := NewHasher(c.opts...)
+		p, root, leaf := depth20Proof(h)
+		if err := h.VerifyInclusion(root, leaf, p); err != nil {
+			t.Fatalf("%v: %v", c.name, err)
+		}
+		if n := testing.AllocsPerRun(100, func() { _ = h.VerifyE(root, leaf, p.LeafIndex, p.Path) }); n != 0 {
+			t.Errorf("%v: VerifyE makes %v allocations", c.name, n)
+		}
+		if n := testing.AllocsPerRun(100, func() { _ = h.VerifyInclusion(root, leaf, p) }); n != 0 {
+			t.Errorf("%v: VerifyInclusion makes %v allocations", c.name, n)
+		}
+	}
+}
+
+func BenchmarkVerifyE(b *testing.B) {
+	for _, c := range allocHashers {
+		h := NewHasher(c.opts...)
+		p, root, leaf := depth20Proof(h)
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := h.VerifyE(root, leaf, p.LeafIndex, p.Path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
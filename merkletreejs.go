@@ -0,0 +1,98 @@
+package merkle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// merkleTreeJSEntry is an entry of a proof of merkletreejs' getProof.
+type merkleTreeJSEntry struct {
+	Position string          `json:"position"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// merkleTreeJSBuffer is a Node.js Buffer as written by JSON.stringify.
+type merkleTreeJSBuffer struct {
+	Type string `json:"type"`
+	Data []int  `json:"data"` // bytes as numbers, not the base64 string of a []byte
+}
+
+// FromMerkleTreeJS decodes a proof of merkletreejs' getProof, an array of
+// {"position": "left"|"right", "data": ...}, where the position tells on which side of the
+// concatenation the sibling goes as for AuditHash. The data is a hex string, with or without
+// 0x, a base64 string, or a Buffer as written by JSON.stringify.
+// The proofs of merkletreejs carry neither the index of the leaf nor the size of the tree, the
+// returned proof only has its Path set: the caller fills in LeafIndex and TreeSize before calling
+// Verify, or passes the Path to VerifyProof. A tree of merkletreejs without the duplicateOdd
+// option has the shape of a Tree, its hash functions are set with the Options.
+// This errors with ErrMalformedProof when data is not such a proof.
+func FromMerkleTreeJS(data []byte) (InclusionProof, error) {
+	var entries []merkleTreeJSEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return InclusionProof{}, fmt.Errorf("%w: %v", ErrMalformedProof, err)
+	}
+	path := make([]AuditHash, len(entries))
+	for i, e := range entries {
+		switch e.Position {
+		case sideLeft:
+		case sideRight:
+			path[i].RightOperator = true
+		default:
+			return InclusionProof{}, fmt.Errorf("%w: entry %v has unknown position %q", ErrMalformedProof, i, e.Position)
+		}
+		val, err := merkleTreeJSData(e.Data)
+		if err != nil {
+			return InclusionProof{}, fmt.Errorf("%w: entry %v: %v", ErrMalformedProof, i, err)
+		}
+		if len(val) == 0 || len(val) > maxHashSize {
+			return InclusionProof{}, fmt.Errorf("%w: entry %v has %v bytes", ErrMalformedProof, i, len(val))
+		}
+		path[i].Val = val
+	}
+	return InclusionProof{Path: path}, nil
+}
+
+// merkleTreeJSData decodes the data of an entry, trying hex before base64 for strings.
+func merkleTreeJSData(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		var b merkleTreeJSBuffer
+		if err := json.Unmarshal(raw, &b); err != nil || b.Type != "Buffer" {
+			return nil, fmt.Errorf("data is neither a string nor a Buffer")
+		}
+		val := make([]byte, len(b.Data))
+		for i, v := range b.Data {
+			if v < 0 || v > 0xff {
+				return nil, fmt.Errorf("byte %v of the Buffer out of range", v)
+			}
+			val[i] = byte(v)
+		}
+		return val, nil
+	}
+	if val, err := unhexify(strings.TrimPrefix(s, "0x")); err == nil {
+		return val, nil
+	}
+	if val, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return val, nil
+	}
+	return nil, fmt.Errorf("data %q is neither hex nor base64", s)
+}
+
+// ToMerkleTreeJS encodes an audit path as a proof of merkletreejs' getProof whose data are 0x
+// prefixed hex strings, which MerkleTree.verify accepts as they are.
+func ToMerkleTreeJS(path []AuditHash) ([]byte, error) {
+	entries := make([]struct {
+		Position string `json:"position"`
+		Data     string `json:"data"`
+	}, len(path))
+	for i, entry := range path {
+		entries[i].Position = sideLeft
+		if entry.RightOperator {
+			entries[i].Position = sideRight
+		}
+		entries[i].Data = "0x" + hexify(entry.Val)
+	}
+	return json.Marshal(entries)
+}
@@ -0,0 +1,73 @@
+package merkle
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// merkleTreeJSFixture is testdata/merkletreejs_proofs.json, every proof being given with its data
+// as Buffers and as hex strings.
+type merkleTreeJSFixture struct {
+	Leaves []string `json:"leaves"`
+	Root   string   `json:"root"`
+	Proofs []struct {
+		Index   int             `json:"index"`
+		Buffers json.RawMessage `json:"buffers"`
+		Hex     json.RawMessage `json:"hex"`
+	} `json:"proofs"`
+}
+
+func TestMerkleTreeJSProofs(t *testing.T) {
+	data, err := os.ReadFile("testdata/merkletreejs_proofs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f merkleTreeJSFixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	items := make([][]byte, len(f.Leaves))
+	for i, leaf := range f.Leaves {
+		items[i] = []byte(leaf)
+	}
+	// hashLeaves hashes the leaves with keccak256 and the nodes are keccak256(left || right).
+	h := NewHasher(WithKeccak256(), WithInsecureRawNodeHashing())
+	root := h.Root(items)
+	if got := "0x" + hexify(root); got != f.Root {
+		t.Fatalf("root %v, merkletreejs has %v", got, f.Root)
+	}
+	for _, c := range f.Proofs {
+		mine, err := h.Prove(items, c.Index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, raw := range []json.RawMessage{c.Buffers, c.Hex} {
+			p, err := FromMerkleTreeJS(raw)
+			if err != nil {
+				t.Fatalf("FromMerkleTreeJS of the proof of %v: %v", c.Index, err)
+			}
+			if !h.VerifyProof(root, items[c.Index], c.Index, p.Path) {
+				t.Errorf("proof of %v does not verify", c.Index)
+			}
+			if !reflect.DeepEqual(p.Path, mine.Path) {
+				t.Errorf("proof of %v: got %v, merkletreejs has %v", c.Index, mine.Path, p.Path)
+			}
+		}
+		out, err := ToMerkleTreeJS(mine.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got, want interface{}
+		if err := json.Unmarshal(out, &got); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(c.Hex, &want); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ToMerkleTreeJS of the proof of %v: got %s, want %s", c.Index, out, c.Hex)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// MMR is a Merkle Mountain Range, an append only accumulator made of perfect binary trees
+// of strictly decreasing heights called peaks.
+//
+// Nodes are numbered from 0 in the order they are added, which is the post-order of the
+// trees: appending a leaf adds the leaf and then every parent it completes, so the first
+// leaves get positions 0, 1, 3, 4, 7, 8, 10, 11. Leaves are hashed with the leaf prefix and
+// parents with the interior prefix like the nodes of a Tree.
+//
+// The root bags the peaks from right to left, H(p0, H(p1, ... H(pn-1, pn))), the root of an
+// empty range is the hash of the empty string.
+type MMR struct {
+	h     *Hasher
+	nodes [][]byte
+}
+
+// NewMMR returns an empty range, the options configure the hash function as for NewHasher.
+func NewMMR(opts ...Option) *MMR {
+	return NewHasher(opts...).NewMMR()
+}
+
+// NewMMR returns an empty range using the Hasher's hash function.
+func (h *Hasher) NewMMR() *MMR {
+	return &MMR{h: h}
+}
+
+// Size returns the number of nodes in the range.
+func (m *MMR) Size() int {
+	return len(m.nodes)
+}
+
+// Append adds a leaf to the range and returns its position.
+func (m *MMR) Append(leaf []byte) int {
+	pos := len(m.nodes)
+	m.nodes = append(m.nodes, m.h.LeafHash(leaf))
+
+	height := 0
+	for mmrHeight(len(m.nodes)) > height {
+		left := len(m.nodes) - (2 << uint(height))
+		right := len(m.nodes) - 1
+		m.nodes = append(m.nodes, m.h.NodeHash(m.nodes[left], m.nodes[right]))
+		height++
+	}
+	return pos
+}
+
+// Root returns the bagged peaks of the range.
+func (m *MMR) Root() []byte {
+	peaks := mmrPeaks(len(m.nodes))
+	if len(peaks) == 0 {
+		return m.h.emptyHash()
+	}
+	return m.bag(peaks)
+}
+
+// bag folds the given peaks from right to left.
+func (m *MMR) bag(peaks []int) []byte {
+	root := m.nodes[peaks[len(peaks)-1]]
+	for i := len(peaks) - 2; i >= 0; i-- {
+		root = m.h.NodeHash(m.nodes[peaks[i]], root)
+	}
+	return root
+}
+
+// Proof returns the audit path of the leaf at position pos: its path to its peak followed by
+// the bagging of the peaks on its right, if any, and then the peaks on its left one at a time.
+// The path verifies with VerifyProof like the ones of Proof.
+// This errors with ErrIndexOutOfBounds when pos is not the position of a leaf.
+func (m *MMR) Proof(pos int) ([]AuditHash, error) {
+	if pos < 0 || pos >= len(m.nodes) || mmrHeight(pos) != 0 {
+		return nil, fmt.Errorf("%w: position %v is not a leaf of a range of size %v", ErrIndexOutOfBounds, pos, len(m.nodes))
+	}
+
+	res := []AuditHash{}
+	peaks := mmrPeaks(len(m.nodes))
+	walkMMR(pos, peaks, func(sibling int, right bool) {
+		res = append(res, AuditHash{m.nodes[sibling], right})
+	})
+
+	peak := peakIndex(pos, peaks)
+	if peak < len(peaks)-1 {
+		res = append(res, AuditHash{m.bag(peaks[peak+1:]), true})
+	}
+	for i := peak - 1; i >= 0; i-- {
+		res = append(res, AuditHash{m.nodes[peaks[i]], false})
+	}
+	return res, nil
+}
+
+// VerifyMMRProof verifies that leaf is at position pos in the range of the given size whose root is root.
+func VerifyMMRProof(root, leaf []byte, pos, size int, path []AuditHash) bool {
+	return defaultHasher.VerifyMMRProof(root, leaf, pos, size, path)
+}
+
+// VerifyMMRProof verifies an MMR proof using the Hasher's hash function.
+// The directions of the path must be the ones implied by pos and size.
+func (h *Hasher) VerifyMMRProof(root, leaf []byte, pos, size int, path []AuditHash) bool {
+	if pos < 0 || pos >= size || mmrHeight(pos) != 0 {
+		return false
+	}
+	peaks := mmrPeaks(size)
+	if len(peaks) == 0 {
+		return false
+	}
+
+	directions := []bool{}
+	walkMMR(pos, peaks, func(_ int, right bool) {
+		directions = append(directions, right)
+	})
+	peak := peakIndex(pos, peaks)
+	if peak < len(peaks)-1 {
+		directions = append(directions, true)
+	}
+	for i := peak - 1; i >= 0; i-- {
+		directions = append(directions, false)
+	}
+
+	if len(directions) != len(path) {
+		return false
+	}
+	for i, right := range directions {
+		if path[i].RightOperator != right {
+			return false
+		}
+	}
+	return h.VerifyLeafHash(root, h.LeafHash(leaf), pos, path)
+}
+
+// walkMMR calls fn with the position of each sibling on the path from pos up to its peak,
+// and whether the sibling is on the right.
+func walkMMR(pos int, peaks []int, fn func(sibling int, right bool)) {
+	height := 0
+	for !isPeak(pos, peaks) {
+		if mmrHeight(pos+1) > height {
+			fn(pos-(2<<uint(height))+1, false)
+			pos++
+		} else {
+			sibling := pos + (2 << uint(height)) - 1
+			fn(sibling, true)
+			pos = sibling + 1
+		}
+		height++
+	}
+}
+
+// mmrHeight returns the height of the node at position pos, leaves having height 0.
+func mmrHeight(pos int) int {
+	p := uint(pos) + 1
+	// Positions of the left most branch are all ones, jump left until reaching it.
+	for p&(p+1) != 0 {
+		p -= 1<<(uint(bits.Len(p))-1) - 1
+	}
+	return bits.Len(p) - 1
+}
+
+// mmrPeaks returns the positions of the peaks of a range of size nodes, from left to right.
+// It returns nil for a size that no sequence of appends produces.
+func mmrPeaks(size int) []int {
+	peaks := []int{}
+	offset := 0
+	prev := 0
+	for size > 0 {
+		// The largest perfect tree that fits has 2^k - 1 nodes, and each peak is smaller than the previous one.
+		tree := 1<<uint(bits.Len(uint(size+1))-1) - 1
+		if prev != 0 && tree >= prev {
+			return nil
+		}
+		peaks = append(peaks, offset+tree-1)
+		offset += tree
+		size -= tree
+		prev = tree
+	}
+	return peaks
+}
+
+func isPeak(pos int, peaks []int) bool {
+	i := peakIndex(pos, peaks)
+	return i >= 0 && peaks[i] == pos
+}
+
+// peakIndex returns the index of the peak whose tree holds pos, or -1.
+func peakIndex(pos int, peaks []int) int {
+	for i, peak := range peaks {
+		if pos <= peak {
+			return i
+		}
+	}
+	return -1
+}
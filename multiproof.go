@@ -0,0 +1,126 @@
+package merkle
+
+import "sort"
+
+// MultiProof proves the inclusion of several leaves of the same tree at once.
+// Hashes holds the roots of the subtrees that contain none of the proven leaves,
+// ordered as they are met by a left to right depth first walk of the tree, so that
+// a node shared by several audit paths is only sent once.
+type MultiProof struct {
+	TreeSize int
+	Indices  []int // sorted and deduplicated
+	Hashes   [][]byte
+}
+
+// NewMultiProof returns the minimal proof for the items at the given indices.
+// Indices may be unsorted and contain duplicates.
+// This errors with ErrEmptyTree when there are no items and ErrIndexOutOfBounds
+// when one of the indices is out of bounds.
+func NewMultiProof(items [][]byte, indices []int) (*MultiProof, error) {
+	return defaultHasher.NewMultiProof(items, indices)
+}
+
+// NewMultiProof returns the minimal proof for the items at the given indices
+// using the Hasher's hash function.
+func (h *Hasher) NewMultiProof(items [][]byte, indices []int) (*MultiProof, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyTree
+	}
+	sorted := make([]int, 0, len(indices))
+	for _, i := range indices {
+		if i < 0 || i >= len(items) {
+			return nil, indexError(i, len(items))
+		}
+		sorted = append(sorted, i)
+	}
+	sorted = dedupe(sorted)
+
+	proof := &MultiProof{
+		TreeSize: len(items),
+		Indices:  sorted,
+		Hashes:   [][]byte{},
+	}
+	h.multiproof(items, 0, sorted, proof)
+	return proof, nil
+}
+
+// multiproof walks the subtree over items, whose first leaf is at offset, and collects
+// the roots of the subtrees that hold none of the indices.
+func (h *Hasher) multiproof(items [][]byte, offset int, indices []int, proof *MultiProof) {
+	if len(indices) == 0 {
+		proof.Hashes = append(proof.Hashes, h.rootAt(offset, items))
+		return
+	}
+	if len(items) == 1 {
+		return
+	}
+
+	k := prevPowerOfTwo(len(items))
+	split := sort.SearchInts(indices, offset+k)
+	h.multiproof(items[:k], offset, indices[:split], proof)
+	h.multiproof(items[k:], offset+k, indices[split:], proof)
+}
+
+// VerifyMultiProof verifies that every leaf is included at its index in the tree whose root is root.
+// The indices of leaves must be exactly the ones the proof was generated for.
+func VerifyMultiProof(root []byte, leaves map[int][]byte, proof *MultiProof) bool {
+	return defaultHasher.VerifyMultiProof(root, leaves, proof)
+}
+
+// VerifyMultiProof verifies a MultiProof using the Hasher's hash function.
+func (h *Hasher) VerifyMultiProof(root []byte, leaves map[int][]byte, proof *MultiProof) bool {
+	if proof == nil || proof.TreeSize <= 0 || len(leaves) != len(proof.Indices) || len(proof.Indices) == 0 {
+		return false
+	}
+	for j, i := range proof.Indices {
+		if _, ok := leaves[i]; !ok || i < 0 || i >= proof.TreeSize || (j > 0 && i <= proof.Indices[j-1]) {
+			return false
+		}
+	}
+	size := h.Size()
+	for _, p := range proof.Hashes {
+		if len(p) != size {
+			return false
+		}
+	}
+
+	hashes := proof.Hashes
+	var walk func(offset, n int, indices []int) []byte
+	walk = func(offset, n int, indices []int) []byte {
+		if len(indices) == 0 {
+			if len(hashes) == 0 {
+				return nil
+			}
+			node := hashes[0]
+			hashes = hashes[1:]
+			return node
+		}
+		if n == 1 {
+			return h.LeafHashAt(offset, leaves[offset])
+		}
+
+		k := prevPowerOfTwo(n)
+		split := sort.SearchInts(indices, offset+k)
+		left := walk(offset, k, indices[:split])
+		right := walk(offset+k, n-k, indices[split:])
+		if left == nil || right == nil {
+			return nil
+		}
+		return h.NodeHash(left, right)
+	}
+
+	computed := walk(0, proof.TreeSize, proof.Indices)
+	return computed != nil && len(hashes) == 0 && equalDigest(root, computed)
+}
+
+// dedupe sorts a slice of ints and removes repeated values in place.
+func dedupe(s []int) []int {
+	sort.Ints(s)
+	out := s[:0]
+	for j, v := range s {
+		if j == 0 || v != s[j-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package merkle
+
+import "testing"
+
+func TestVerifyMultiProof(t *testing.T) {
+	items := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d"), []byte("e")}
+	root := Root(items)
+	for _, indices := range [][]int{{0}, {4}, {1, 2}, {3, 0, 3}, {0, 1, 2, 3, 4}} {
+		proof, err := NewMultiProof(items, indices)
+		if err != nil {
+			t.Fatalf("NewMultiProof(%v): %v", indices, err)
+		}
+		leaves := map[int][]byte{}
+		for _, i := range indices {
+			leaves[i] = items[i]
+		}
+		if !VerifyMultiProof(root, leaves, proof) {
+			t.Errorf("VerifyMultiProof(%v) failed", indices)
+		}
+	}
+}
+
+// TestVerifyMultiProofNegativeIndex claims a leaf at index -1, which the walk of the tree never
+// reaches, besides the real leaf at index 0 of a tree of two items.
+func TestVerifyMultiProofNegativeIndex(t *testing.T) {
+	items := [][]byte{[]byte("a"), []byte("b")}
+	proof := &MultiProof{TreeSize: 2, Indices: []int{-1, 0}, Hashes: [][]byte{LeafHash(items[1])}}
+	leaves := map[int][]byte{-1: []byte("EVIL"), 0: items[0]}
+	if VerifyMultiProof(Root(items), leaves, proof) {
+		t.Fatal("a leaf at a negative index verified")
+	}
+}
@@ -0,0 +1,191 @@
+package merkle
+
+import "fmt"
+
+// NaryTree is a merkle tree whose interior nodes have up to arity children, for proofs that are
+// shallower than those of a Tree at the cost of more siblings per level.
+// Each level is split into groups of arity consecutive nodes, from the left. A group of m >= 2
+// nodes is hashed as H(0x01 || c1 || ... || cm) with the prefixes of the Hasher, so that the last
+// group of a level can have fewer children than the others, and a last group of a single node is
+// carried up unchanged. With arity 2 this is the shape and the hashing of a Tree, and the roots
+// are those of Root.
+type NaryTree struct {
+	h      *Hasher
+	arity  int
+	levels [][][]byte
+}
+
+// NaryAuditLevel is the part of the audit path of a NaryTree at one level: the other children of
+// the group of the node reached so far, in order, and the position of that node in the group.
+type NaryAuditLevel struct {
+	Siblings [][]byte
+	Position int
+}
+
+// NewNaryTree hashes the items into a tree of the given arity, the options configure the hash
+// function as for NewHasher.
+// This errors with ErrInvalidArity when arity is smaller than 2.
+func NewNaryTree(items [][]byte, arity int, opts ...Option) (*NaryTree, error) {
+	return NewHasher(opts...).NewNaryTree(items, arity)
+}
+
+// NewNaryTree hashes the items into a tree of the given arity using the Hasher's hash function.
+// This errors with ErrInvalidArity when arity is smaller than 2, or larger than 2 for a Hasher set
+// WithNodeHasher, whose nodes have two children.
+func (h *Hasher) NewNaryTree(items [][]byte, arity int) (*NaryTree, error) {
+	if err := h.checkArity(arity); err != nil {
+		return nil, err
+	}
+	t := &NaryTree{h: h, arity: arity}
+	if len(items) == 0 {
+		return t, nil
+	}
+	level := make([][]byte, len(items))
+	for i, item := range items {
+		level[i] = h.LeafHashAt(i, item)
+	}
+	t.levels = append(t.levels, level)
+	for len(level) > 1 {
+		// The number of groups and their bounds are computed without adding arity, which may be
+		// as large as an int.
+		next := make([][]byte, (len(level)-1)/arity+1)
+		for j := range next {
+			lo := j * arity
+			next[j] = h.naryNode(level[lo : lo+minInt(arity, len(level)-lo)])
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+func (h *Hasher) checkArity(arity int) error {
+	if arity < 2 {
+		return fmt.Errorf("%w: arity %v, expected at least 2", ErrInvalidArity, arity)
+	}
+	if arity > 2 && h.nodeHasher != nil {
+		return fmt.Errorf("%w: arity %v, a NodeHasher hashes nodes of 2 children", ErrInvalidArity, arity)
+	}
+	return nil
+}
+
+// naryNode returns the node of a group of children, the child itself when it is alone.
+func (h *Hasher) naryNode(children [][]byte) []byte {
+	switch len(children) {
+	case 1:
+		return children[0]
+	case 2:
+		return h.NodeHash(children[0], children[1])
+	}
+	d := h.acquire()
+	d.Write(h.interiorPrefix)
+	for _, c := range children {
+		d.Write(c)
+	}
+	res := d.Sum(nil)
+	h.pool.Put(d)
+	return res
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Arity returns the largest number of children of the nodes of the tree.
+func (t *NaryTree) Arity() int {
+	return t.arity
+}
+
+// Len returns the number of items of the tree.
+func (t *NaryTree) Len() int {
+	if len(t.levels) == 0 {
+		return 0
+	}
+	return len(t.levels[0])
+}
+
+// Root returns the root hash of the tree, the root of the empty tree when it has no items.
+func (t *NaryTree) Root() []byte {
+	if len(t.levels) == 0 {
+		return t.h.emptyHash()
+	}
+	return append([]byte(nil), t.levels[len(t.levels)-1][0]...)
+}
+
+// Proof returns the audit path of the item at index i, from the leaf up. The levels where the
+// node is carried up alone have no entry.
+// This errors with ErrEmptyTree when the tree has no items and ErrIndexOutOfBounds when the
+// index is out of bounds.
+func (t *NaryTree) Proof(i int) ([]NaryAuditLevel, error) {
+	if t.Len() == 0 {
+		return nil, ErrEmptyTree
+	}
+	if i < 0 || i >= t.Len() {
+		return nil, indexError(i, t.Len())
+	}
+	var path []NaryAuditLevel
+	for _, level := range t.levels[:len(t.levels)-1] {
+		lo := i / t.arity * t.arity
+		group := level[lo : lo+minInt(t.arity, len(level)-lo)]
+		if len(group) > 1 {
+			e := NaryAuditLevel{Position: i - lo}
+			for k, c := range group {
+				if lo+k != i {
+					e.Siblings = append(e.Siblings, append([]byte(nil), c...))
+				}
+			}
+			path = append(path, e)
+		}
+		i /= t.arity
+	}
+	return path, nil
+}
+
+// VerifyNaryProof verifies that leaf is included at index in the tree of treeSize items of the
+// given arity whose root hash is root.
+func VerifyNaryProof(root, leaf []byte, index, treeSize, arity int, path []NaryAuditLevel) bool {
+	return defaultHasher.VerifyNaryProof(root, leaf, index, treeSize, arity, path)
+}
+
+// VerifyNaryProof verifies a NaryTree inclusion proof using the Hasher's hash function.
+// The index and tree size fix the group of the node at every level, so every level must have
+// exactly the other children of that group, of the digest size, and the position of the node in
+// it, the digit of index in base arity for that level, the levels where the node is alone having
+// no entry.
+func (h *Hasher) VerifyNaryProof(root, leaf []byte, index, treeSize, arity int, path []NaryAuditLevel) bool {
+	size := h.Size()
+	if h.checkArity(arity) != nil || index < 0 || index >= treeSize || len(root) != size {
+		return false
+	}
+	node := h.LeafHashAt(index, leaf)
+	k := 0
+	for i, n := index, treeSize; n > 1; i, n = i/arity, (n-1)/arity+1 {
+		lo := i / arity * arity
+		width := minInt(arity, n-lo)
+		if width == 1 {
+			continue
+		}
+		if k == len(path) {
+			return false
+		}
+		e := path[k]
+		k++
+		if len(e.Siblings) != width-1 || e.Position != i-lo {
+			return false
+		}
+		group := make([][]byte, 0, width)
+		group = append(group, e.Siblings[:e.Position]...)
+		group = append(group, node)
+		group = append(group, e.Siblings[e.Position:]...)
+		for _, c := range group {
+			if len(c) != size {
+				return false
+			}
+		}
+		node = h.naryNode(group)
+	}
+	return k == len(path) && equalDigest(root, node)
+}
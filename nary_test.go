@@ -0,0 +1,78 @@
+package merkle
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// naryGroup hashes a group of children as H(0x01 || c1 || ... || cm) with SHA3-256.
+func naryGroup(children ...[]byte) []byte {
+	d := sha3.New256()
+	d.Write([]byte{0x01})
+	for _, c := range children {
+		d.Write(c)
+	}
+	return d.Sum(nil)
+}
+
+// TestNaryTreePartialGroups pins the rule for the last group of a level: a group of m >= 2 nodes
+// is hashed with its m children and a group of a single node is carried up unchanged.
+func TestNaryTreePartialGroups(t *testing.T) {
+	items := testItems(8)
+	l := make([][]byte, len(items))
+	for i, item := range items {
+		l[i] = LeafHash(item)
+	}
+	for _, c := range []struct {
+		n    int
+		want []byte
+	}{
+		{1, l[0]},
+		{2, naryGroup(l[0], l[1])},
+		{4, naryGroup(naryGroup(l[0], l[1], l[2]), l[3])},
+		{7, naryGroup(naryGroup(l[0], l[1], l[2]), naryGroup(l[3], l[4], l[5]), l[6])},
+		{8, naryGroup(naryGroup(l[0], l[1], l[2]), naryGroup(l[3], l[4], l[5]), naryGroup(l[6], l[7]))},
+	} {
+		tree, err := NewNaryTree(items[:c.n], 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tree.Root(); !equalDigest(got, c.want) {
+			t.Errorf("root of %v items = %x, want %x", c.n, got, c.want)
+		}
+	}
+}
+
+func TestNaryTreeProofs(t *testing.T) {
+	for _, arity := range []int{2, 3, 4, 16, math.MaxInt} {
+		for _, n := range []int{1, 2, 3, 5, 9, 17, 40} {
+			items := testItems(n)
+			tree, err := NewNaryTree(items, arity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if arity == 2 && !equalDigest(tree.Root(), Root(items)) {
+				t.Errorf("binary NaryTree of %v items is not the Tree", n)
+			}
+			for i := range items {
+				path, err := tree.Proof(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !VerifyNaryProof(tree.Root(), items[i], i, n, arity, path) {
+					t.Errorf("arity %v, %v items: proof of %v does not verify", arity, n, i)
+				}
+				for _, j := range []int{i - 1, i + 1, i + arity} {
+					if j >= 0 && VerifyNaryProof(tree.Root(), items[i], j, n, arity, path) {
+						t.Errorf("arity %v, %v items: proof of %v verifies at index %v", arity, n, i, j)
+					}
+				}
+				if i == n-1 && VerifyNaryProof(tree.Root(), items[i], i, n+1, arity, path) {
+					t.Errorf("arity %v, %v items: proof of the last item verifies in a larger tree", arity, n)
+				}
+			}
+		}
+	}
+}
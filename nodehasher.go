@@ -0,0 +1,199 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// NodeHasher hashes the leaves and interior nodes of a tree, for hash functions that are not a
+// hash.Hash over bytes, such as Poseidon over field elements for proofs verified in a circuit.
+// Every digest must have Size bytes, HashLeaf and HashNode must not modify their arguments and
+// may be called concurrently. A Hasher is itself a NodeHasher, with the default SHA3-256 hashing.
+type NodeHasher interface {
+	HashLeaf(data []byte) []byte
+	HashNode(left, right []byte) []byte
+	EmptyRoot() []byte
+	Size() int
+}
+
+// WithNodeHasher hashes leaves, interior nodes and the empty tree with nh, which separates leaves
+// from nodes by itself: no prefix is written. The options changing the data of a leaf, such as
+// WithLeafIndexBinding, apply as for the other hash functions, the data being passed to HashLeaf.
+// Proofs serialized by the Hasher are marked with HashCustom.
+// NewHasher panics when nh is nil, its Size is not between 1 and 255 bytes, the limit of the binary
+// encodings, or its EmptyRoot does not have that size.
+func WithNodeHasher(nh NodeHasher) Option {
+	return func(h *Hasher) {
+		if nh == nil {
+			panic("merkle: nil NodeHasher")
+		}
+		withNamedHash(HashCustom, nil)(h)
+		h.nodeHasher = nh
+		h.leafPrefix = nil
+		h.interiorPrefix = nil
+		h.unsafePrefixes = true
+	}
+}
+
+// initNodeHasher completes NewHasher for a Hasher delegating to a NodeHasher.
+func (h *Hasher) initNodeHasher() {
+	h.size = h.nodeHasher.Size()
+	if h.size < 1 || h.size > 255 {
+		panic(fmt.Sprintf("merkle: NodeHasher digests of %v bytes, expected 1 to 255", h.size))
+	}
+	h.empty = append([]byte(nil), h.nodeHasher.EmptyRoot()...)
+	if len(h.empty) != h.size {
+		panic(fmt.Sprintf("merkle: NodeHasher empty root of %v bytes, expected %v", len(h.empty), h.size))
+	}
+	h.bufs.New = func() interface{} {
+		b := make([]byte, 0, h.size)
+		return &b
+	}
+}
+
+// nodeHasherLeaf appends the hash of a leaf to dst with the Hasher's NodeHasher, index being the
+// encoded index of the leaf or nil.
+func (h *Hasher) nodeHasherLeaf(dst, index, data []byte) []byte {
+	if index == nil && h.leafEncoding != LeafLengthPrefixed {
+		return append(dst, h.nodeHasher.HashLeaf(data)...)
+	}
+	buf := make([]byte, 0, len(index)+binary.MaxVarintLen64+len(data))
+	buf = append(buf, index...)
+	if h.leafEncoding == LeafLengthPrefixed {
+		var n [binary.MaxVarintLen64]byte
+		buf = append(buf, n[:binary.PutUvarint(n[:], uint64(len(data)))]...)
+	}
+	buf = append(buf, data...)
+	return append(dst, h.nodeHasher.HashLeaf(buf)...)
+}
+
+// HashLeaf returns LeafHash(data), so that a Hasher is a NodeHasher.
+func (h *Hasher) HashLeaf(data []byte) []byte {
+	return h.LeafHash(data)
+}
+
+// HashNode returns NodeHash(left, right).
+func (h *Hasher) HashNode(left, right []byte) []byte {
+	return h.NodeHash(left, right)
+}
+
+// EmptyRoot returns the root of the empty tree.
+func (h *Hasher) EmptyRoot() []byte {
+	return h.emptyHash()
+}
+
+// CheckNodeHasher runs the conformance checks of a NodeHasher and returns the first failure,
+// wrapping ErrInvalidNodeHasher. It checks the sizes of the digests, that hashing is deterministic
+// and leaves its arguments unmodified, that leaves, nodes and the order of children are separated,
+// and that roots, audit paths and their binary encoding work end to end through WithNodeHasher.
+// Implementations can call it from their own tests.
+func CheckNodeHasher(nh NodeHasher) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrInvalidNodeHasher, r)
+		}
+	}()
+	fail := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidNodeHasher}, args...)...)
+	}
+
+	size := nh.Size()
+	if size < 1 || size > 255 {
+		return fail("size %v, expected 1 to 255", size)
+	}
+	sized := func(name string, d []byte) error {
+		if len(d) != size {
+			return fail("%v has %v bytes, expected %v", name, len(d), size)
+		}
+		return nil
+	}
+	if err := sized("EmptyRoot", nh.EmptyRoot()); err != nil {
+		return err
+	}
+	if !equalDigest(nh.EmptyRoot(), nh.EmptyRoot()) {
+		return fail("EmptyRoot is not deterministic")
+	}
+
+	items := [][]byte{nil, []byte("a"), []byte("b"), []byte("abc"), bytes.Repeat([]byte{0xa5}, 1000)}
+	leaves := make([][]byte, len(items))
+	for i, item := range items {
+		saved := append([]byte(nil), item...)
+		leaves[i] = nh.HashLeaf(item)
+		if err := sized(fmt.Sprintf("HashLeaf of item %v", i), leaves[i]); err != nil {
+			return err
+		}
+		if !bytes.Equal(item, saved) { // value
+			return fail("HashLeaf modified item %v", i)
+		}
+		if !equalDigest(leaves[i], nh.HashLeaf(item)) {
+			return fail("HashLeaf of item %v is not deterministic", i)
+		}
+		for j := 0; j < i; j++ {
+			if equalDigest(leaves[i], leaves[j]) {
+				return fail("items %v and %v have the same leaf hash", j, i)
+			}
+		}
+	}
+
+	l, r := leaves[1], leaves[2]
+	sl, sr := append([]byte(nil), l...), append([]byte(nil), r...)
+	node := nh.HashNode(l, r)
+	if err := sized("HashNode", node); err != nil {
+		return err
+	}
+	switch {
+	case !bytes.Equal(l, sl) || !bytes.Equal(r, sr): // value
+		return fail("HashNode modified its children")
+	case !equalDigest(node, nh.HashNode(l, r)):
+		return fail("HashNode is not deterministic")
+	case equalDigest(node, nh.HashNode(r, l)):
+		return fail("HashNode does not depend on the order of the children")
+	case equalDigest(node, nh.HashLeaf(append(append([]byte(nil), l...), r...))):
+		return fail("HashNode(l, r) equals HashLeaf(l || r), leaves are not separated from nodes")
+	}
+
+	h := NewHasher(WithNodeHasher(nh))
+	if !equalDigest(h.Root(nil), nh.EmptyRoot()) {
+		return fail("the root of the empty tree is not EmptyRoot")
+	}
+	var fold func(leaves [][]byte) []byte
+	fold = func(leaves [][]byte) []byte {
+		if len(leaves) == 1 {
+			return leaves[0]
+		}
+		k := prevPowerOfTwo(len(leaves))
+		return nh.HashNode(fold(leaves[:k]), fold(leaves[k:]))
+	}
+	t := h.NewTree(items)
+	root := t.Root()
+	if !equalDigest(root, fold(leaves)) {
+		return fail("the root of the tree is not the fold of HashLeaf and HashNode")
+	}
+	for i, item := range items {
+		p, err := t.Proof(i)
+		if err != nil {
+			return fail("proof of item %v: %v", i, err)
+		}
+		if !h.VerifyProof(root, item, i, p) {
+			return fail("proof of item %v does not verify", i)
+		}
+		b, err := h.MarshalInclusionProof(InclusionProof{LeafIndex: i, TreeSize: len(items), Path: p})
+		if err != nil {
+			return fail("encoding the proof of item %v: %v", i, err)
+		}
+		dp, err := h.UnmarshalInclusionProof(b)
+		if err != nil {
+			return fail("decoding the proof of item %v: %v", i, err)
+		}
+		if !h.VerifyProof(root, item, i, dp.Path) {
+			return fail("decoded proof of item %v does not verify", i)
+		}
+		p[0].Val = append([]byte(nil), p[0].Val...)
+		p[0].Val[0] ^= 1
+		if h.VerifyProof(root, item, i, p) {
+			return fail("altered proof of item %v verifies", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// maxPaddedDepth is the largest depth of a PaddedTree, whose capacity must fit in an int.
+const maxPaddedDepth = bits.UintSize - 2
+
+// PaddedTree is an append only tree of fixed depth whose leaves past the ones appended are the
+// zero leaf, the hash of the empty string, as in a SparseTree. Unlike a Tree its shape does not
+// depend on the number of leaves: every audit path has exactly depth entries, as circuits and some
+// on-chain verifiers require, and the roots differ from those of Root for the same leaves.
+//
+// The roots of the subtrees holding no leaf are precomputed, zero(0) being the zero leaf and
+// zero(l+1) = NodeHash(zero(l), zero(l)), so that only the depth nodes above a new leaf are hashed.
+type PaddedTree struct {
+	h      *Hasher
+	depth  int
+	zeros  [][]byte   // zeros[l] is the root of an empty subtree of height l
+	levels [][][]byte // levels[l] are the nodes of height l covering the leaves appended
+}
+
+// NewPaddedTree returns an empty tree of the given depth holding up to 2^depth leaves,
+// the options configure the hash function as for NewHasher.
+// It panics when depth is negative or larger than the bit size of an int minus 2.
+func NewPaddedTree(depth int, opts ...Option) *PaddedTree {
+	return NewHasher(opts...).NewPaddedTree(depth)
+}
+
+// NewPaddedTree returns an empty tree of the given depth using the Hasher's hash function.
+// It panics when depth is negative or larger than the bit size of an int minus 2.
+func (h *Hasher) NewPaddedTree(depth int) *PaddedTree {
+	if depth < 0 || depth > maxPaddedDepth {
+		panic(fmt.Sprintf("merkle: padded tree depth %v, expected 0 to %v", depth, maxPaddedDepth))
+	}
+	return &PaddedTree{h: h, depth: depth, zeros: h.paddedZeros(depth), levels: make([][][]byte, depth+1)}
+}
+
+// paddedZeros returns the roots of the empty subtrees of height 0 to depth.
+func (h *Hasher) paddedZeros(depth int) [][]byte {
+	zeros := make([][]byte, depth+1)
+	zeros[0] = h.emptyHash()
+	for l := 1; l <= depth; l++ {
+		zeros[l] = h.NodeHash(zeros[l-1], zeros[l-1])
+	}
+	return zeros
+}
+
+// Depth returns the depth of the tree, the length of its audit paths.
+func (p *PaddedTree) Depth() int {
+	return p.depth
+}
+
+// Capacity returns the number of leaves the tree holds, 2^depth.
+func (p *PaddedTree) Capacity() int {
+	return 1 << uint(p.depth)
+}
+
+// Len returns the number of leaves appended.
+func (p *PaddedTree) Len() int {
+	return len(p.levels[0])
+}
+
+// ZeroHash returns the root of an empty subtree of the given height, the zero leaf at height 0
+// and the root of the empty tree at height Depth.
+// This errors with ErrIndexOutOfBounds when the height is not between 0 and Depth.
+func (p *PaddedTree) ZeroHash(height int) ([]byte, error) {
+	if height < 0 || height > p.depth {
+		return nil, fmt.Errorf("%w: height %v, tree has depth %v", ErrIndexOutOfBounds, height, p.depth)
+	}
+	return append([]byte(nil), p.zeros[height]...), nil
+}
+
+// Append adds a leaf after the ones appended and returns its index, rehashing the depth nodes above it.
+// This errors with ErrTreeFull when the tree already holds Capacity leaves.
+func (p *PaddedTree) Append(leaf []byte) (int, error) {
+	i := p.Len()
+	if i == p.Capacity() {
+		return 0, fmt.Errorf("%w: padded tree of depth %v holds %v leaves", ErrTreeFull, p.depth, i)
+	}
+	node := p.h.LeafHashAt(i, leaf)
+	p.levels[0] = append(p.levels[0], node)
+	for l, j := 0, i; l < p.depth; l, j = l+1, j>>1 {
+		if j&1 == 0 {
+			node = p.h.NodeHash(node, p.zeros[l])
+		} else {
+			node = p.h.NodeHash(p.levels[l][j-1], node)
+		}
+		if j>>1 < len(p.levels[l+1]) {
+			p.levels[l+1][j>>1] = node
+		} else {
+			p.levels[l+1] = append(p.levels[l+1], node)
+		}
+	}
+	return i, nil
+}
+
+// Root returns the root hash of the tree, the root of the empty subtree of height Depth when
+// no leaf was appended.
+func (p *PaddedTree) Root() []byte {
+	return append([]byte(nil), p.node(p.depth, 0)...)
+}
+
+// Proof returns the audit path of the leaf at index i, which has exactly Depth entries.
+// This errors with ErrIndexOutOfBounds when no leaf was appended at i.
+func (p *PaddedTree) Proof(i int) ([]AuditHash, error) {
+	if i < 0 || i >= p.Len() {
+		return nil, indexError(i, p.Len())
+	}
+	path := make([]AuditHash, p.depth)
+	for l, j := 0, i; l < p.depth; l, j = l+1, j>>1 {
+		path[l] = AuditHash{p.node(l, j^1), j&1 == 0}
+	}
+	return path, nil
+}
+
+// node returns the node of height l at index j, the root of an empty subtree past the leaves appended.
+func (p *PaddedTree) node(l, j int) []byte {
+	if j < len(p.levels[l]) {
+		return p.levels[l][j]
+	}
+	return p.zeros[l]
+}
+
+// VerifyPaddedProof verifies that leaf is at index in the padded tree of the given depth whose root is root.
+func VerifyPaddedProof(root, leaf []byte, index, depth int, path []AuditHash) bool {
+	return defaultHasher.VerifyPaddedProof(root, leaf, index, depth, path)
+}
+
+// VerifyPaddedProof verifies a padded tree inclusion proof using the Hasher's hash function.
+// The path must have exactly depth entries whose directions are the bits of index.
+func (h *Hasher) VerifyPaddedProof(root, leaf []byte, index, depth int, path []AuditHash) bool {
+	size := h.Size()
+	if depth < 0 || depth > maxPaddedDepth || index < 0 || index >= 1<<uint(depth) || len(path) != depth || len(root) != size {
+		return false
+	}
+	node := h.LeafHashAt(index, leaf)
+	for l, j := 0, index; l < depth; l, j = l+1, j>>1 {
+		e := path[l]
+		if len(e.Val) != size || e.RightOperator != (j&1 == 0) {
+			return false
+		}
+		if e.RightOperator {
+			node = h.NodeHash(node, e.Val)
+		} else {
+			node = h.NodeHash(e.Val, node)
+		}
+	}
+	return equalDigest(root, node)
+}
@@ -0,0 +1,145 @@
+package merkle
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// minParallelItems is the size below which a subtree is hashed on the calling goroutine,
+// splitting smaller subtrees costs more than it saves.
+const minParallelItems = 1024
+
+// RootParallel returns the same root as Root, hashing subtrees on up to workers goroutines.
+// A workers value <= 0 defaults to GOMAXPROCS.
+func RootParallel(items [][]byte, workers int) []byte {
+	return defaultHasher.RootParallel(items, workers)
+}
+
+// RootParallel returns the same root as the Hasher's Root, hashing subtrees on up to workers goroutines.
+// A workers value <= 0 defaults to GOMAXPROCS.
+// The function set by WithProgress is called with the hashes of the whole operation, as for Root,
+// and never concurrently. A panic on a goroutine hashing a subtree, such as one of that function,
+// is raised again on the calling goroutine once every subtree is done.
+func (h *Hasher) RootParallel(items [][]byte, workers int) []byte {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var s *sharedProgress
+	if h.progress != nil && len(items) > 0 {
+		s = &sharedProgress{p: h.newProgress(context.Background(), 2*len(items)-1)}
+	}
+	return h.rootParallel(items, 0, workers, s)
+}
+
+// rootParallel splits items, whose first leaf is at offset, at the same prevPowerOfTwo boundary
+// as Root and shares the workers between both halves, counting the hashes with s when it is not nil.
+func (h *Hasher) rootParallel(items [][]byte, offset, workers int, s *sharedProgress) []byte {
+	if workers <= 1 || len(items) < minParallelItems {
+		if s == nil {
+			return h.rootAt(offset, items)
+		}
+		root, _ := h.rootCounted(s.part(h, 2*len(items)-1), offset, items)
+		return root
+	}
+
+	k := prevPowerOfTwo(len(items))
+	var left []byte
+	var failure interface{}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() { failure = recover() }()
+		left = h.rootParallel(items[:k], offset, workers-workers/2, s)
+	}()
+	right := func() []byte {
+		// The left half is waited for even when the right half panics, so that nothing of the
+		// operation runs once it has returned.
+		defer wg.Wait()
+		return h.rootParallel(items[k:], offset+k, workers/2, s)
+	}()
+	if failure != nil {
+		panic(failure)
+	}
+
+	root := h.NodeHash(left, right)
+	if s != nil {
+		s.add(1)
+	}
+	return root
+}
+
+// sharedProgress counts the hashes of a RootParallel. Each subtree hashed on its own counts its
+// hashes with a progress of its own whose reports are added to p under mu, so that the progress
+// function sees the totals of the whole operation and is never called concurrently.
+type sharedProgress struct {
+	mu sync.Mutex
+	p  *progress
+}
+
+// part returns the progress of a subtree of total hashes, reporting them to s.
+func (s *sharedProgress) part(h *Hasher, total int) *progress {
+	p := h.newProgress(context.Background(), total)
+	reported := 0
+	p.fn = func(done, _ int) {
+		s.add(done - reported)
+		reported = done
+	}
+	return p
+}
+
+// add counts n more hashes of the operation.
+func (s *sharedProgress) add(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.p.done += n - 1
+	s.p.step()
+}
+
+// ProofsFor returns the audit paths of the leaves at indices, keyed by index, computing them on up
+// to workers goroutines that share the nodes of the tree. A workers value <= 0 defaults to GOMAXPROCS.
+// The indices are all checked before any path is computed, so the result does not depend on the
+// number of workers.
+// This errors with ErrEmptyTree when the tree has no items and ErrIndexOutOfBounds for the first
+// index that is out of bounds.
+func (t *Tree) ProofsFor(indices []int, workers int) (map[int][]AuditHash, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.leafCount() == 0 {
+		return nil, ErrEmptyTree
+	}
+	for _, i := range indices {
+		if i < 0 || i >= t.leafCount() {
+			return nil, indexError(i, t.leafCount())
+		}
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	paths := make([][]AuditHash, len(indices))
+	chunk := (len(indices) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(indices); lo += chunk {
+		hi := lo + chunk
+		if hi > len(indices) {
+			hi = len(indices)
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			for j := lo; j < hi; j++ {
+				paths[j], _ = t.proof(indices[j])
+			}
+		}(lo, hi)
+	}
+	wg.Wait()
+
+	res := make(map[int][]AuditHash, len(indices))
+	for j, i := range indices {
+		res[i] = paths[j]
+	}
+	return res, nil
+}
@@ -0,0 +1,84 @@
+package merkle
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func testItems(n int) [][]byte {
+	items := make([][]byte, n)
+	for i := range items {
+		items[i] = []byte(fmt.Sprintf("item %v", i))
+	}
+	return items
+}
+
+func TestRootParallel(t *testing.T) {
+	for _, n := range []int{0, 1, 5, minParallelItems, 3*minParallelItems + 7} {
+		items := testItems(n)
+		want := Root(items)
+		for _, workers := range []int{0, 1, 2, 3, 8} {
+			if got := RootParallel(items, workers); !equalDigest(got, want) {
+				t.Errorf("RootParallel(%v items, %v workers) = %x, want %x", n, workers, got, want)
+			}
+		}
+	}
+}
+
+func TestRootParallelProgress(t *testing.T) {
+	n := 5*minParallelItems + 3
+	var inFlight int32
+	last, calls := 0, 0
+	h := NewHasher(WithProgressInterval(100), WithProgress(func(done, total int) {
+		if atomic.AddInt32(&inFlight, 1) != 1 {
+			t.Error("progress function called concurrently")
+		}
+		defer atomic.AddInt32(&inFlight, -1)
+		if total != 2*n-1 {
+			t.Errorf("total = %v, want %v", total, 2*n-1)
+		}
+		if done <= last || done > total {
+			t.Errorf("done = %v after %v, total %v", done, last, total)
+		}
+		last = done
+		calls++
+	}))
+	items := testItems(n)
+	if got, want := h.RootParallel(items, 4), Root(items); !equalDigest(got, want) {
+		t.Fatalf("RootParallel = %x, want %x", got, want)
+	}
+	if last != 2*n-1 || calls < 2 {
+		t.Fatalf("last report %v after %v calls, want %v", last, calls, 2*n-1)
+	}
+}
+
+func TestRootParallelProgressPanic(t *testing.T) {
+	h := NewHasher(WithProgressInterval(64), WithProgress(func(done, total int) {
+		if done > total/2 {
+			panic("stop")
+		}
+	}))
+	defer func() {
+		if r := recover(); r != "stop" {
+			t.Fatalf("recovered %v, want the panic of the progress function", r)
+		}
+	}()
+	h.RootParallel(testItems(4*minParallelItems), 4)
+	t.Fatal("RootParallel returned")
+}
+
+func BenchmarkRootParallel(b *testing.B) {
+	items := testItems(1 << 16)
+	b.Run("NoProgress", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			RootParallel(items, 4)
+		}
+	})
+	b.Run("NoOpProgress", func(b *testing.B) {
+		h := NewHasher(WithProgress(func(done, total int) {}))
+		for i := 0; i < b.N; i++ {
+			h.RootParallel(items, 4)
+		}
+	})
+}
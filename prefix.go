@@ -0,0 +1,88 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// A tree that only grew by appending holds every earlier tree as a prefix of its leaves, the
+// nodes of a prefix being those of the tree where they are complete and hashed on the way up
+// on the right edge of the prefix. Proofs for earlier sizes are served from the current nodes.
+
+// RootAtSize returns the root hash of the tree over the first size leaves of the tree.
+// This errors with ErrIndexOutOfBounds when size is negative or larger than the tree.
+func (t *Tree) RootAtSize(size int) ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if size < 0 || size > t.leafCount() {
+		return nil, fmt.Errorf("%w: size %v, tree has %v items", ErrIndexOutOfBounds, size, t.leafCount())
+	}
+	if size == 0 {
+		return t.h.emptyHash(), nil
+	}
+	return t.subtreeNode(0, size), nil
+}
+
+// ProofAtSize returns the inclusion proof of the leaf at index i in the tree over the first size
+// leaves of the tree, which verifies against RootAtSize(size).
+// This errors with ErrIndexOutOfBounds when size is larger than the tree or i is not below size.
+func (t *Tree) ProofAtSize(i, size int) (InclusionProof, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if size > t.leafCount() {
+		return InclusionProof{}, fmt.Errorf("%w: size %v, tree has %v items", ErrIndexOutOfBounds, size, t.leafCount())
+	}
+	if i < 0 || i >= size {
+		return InclusionProof{}, indexError(i, size)
+	}
+
+	path := []AuditHash{}
+	walkPath(i, size, func(level, node, sibling int) {
+		path = append(path, AuditHash{t.nodeAt(level, sibling, size), sibling > node})
+	})
+	return InclusionProof{LeafIndex: i, TreeSize: size, Path: path}, nil
+}
+
+// ConsistencyProof returns the RFC 6962 consistency proof between the trees over the first
+// oldSize and newSize leaves of the tree, as the package level ConsistencyProof does for their items.
+// This errors with ErrIndexOutOfBounds when the sizes are not ordered within the size of the tree.
+func (t *Tree) ConsistencyProof(oldSize, newSize int) ([]AuditHash, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if oldSize < 0 || oldSize > newSize || newSize > t.leafCount() {
+		return nil, fmt.Errorf("%w: old size %v, new size %v, tree has %v items", ErrIndexOutOfBounds, oldSize, newSize, t.leafCount())
+	}
+	if oldSize == 0 || oldSize == newSize {
+		return []AuditHash{}, nil
+	}
+	return t.subproof(oldSize, 0, newSize, true), nil
+}
+
+// subproof implements SUBPROOF(m, D[n], b) from RFC 6962 section 2.1.2 for the n leaves of the
+// tree starting at offset, as Hasher.subproof does for items.
+func (t *Tree) subproof(m, offset, n int, complete bool) []AuditHash {
+	if m == n {
+		if complete {
+			return []AuditHash{}
+		}
+		return []AuditHash{{t.subtreeNode(offset, n), false}}
+	}
+
+	k := prevPowerOfTwo(n)
+	if m <= k {
+		res := t.subproof(m, offset, k, complete)
+		return append(res, AuditHash{t.subtreeNode(offset+k, n-k), true})
+	}
+	res := t.subproof(m-k, offset+k, n-k, false)
+	return append(res, AuditHash{t.subtreeNode(offset, k), false})
+}
+
+// subtreeNode returns the root of the n leaves starting at offset, which must be aligned on the
+// power of two covering n as the subtrees of the RFC 6962 decomposition are.
+func (t *Tree) subtreeNode(offset, n int) []byte {
+	level := bits.Len(uint(n - 1))
+	return t.nodeAt(level, offset>>uint(level), offset+n)
+}
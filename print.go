@@ -0,0 +1,72 @@
+package merkle
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxPrintLeaves is the number of leaves above which a tree is only summarized.
+	maxPrintLeaves = 32
+	// printHashLen is the number of hex characters of each hash that are printed.
+	printHashLen = 8
+)
+
+// Sprint draws the merkle tree of the items, see Tree.String.
+func Sprint(items [][]byte) string {
+	return NewTree(items).String()
+}
+
+// String draws the tree with one node per line, children indented below their parent and
+// leaves labelled with their index. Only the first printHashLen hex characters of each hash
+// are shown. Trees of more than maxPrintLeaves leaves are summarized on a single line.
+//
+// A tree of 3 leaves is drawn like:
+//
+//	4f8a1c2e
+//	├── 9b0d5e7a
+//	│   ├── [0] 1c3e5a7b
+//	│   └── [1] 2d4f6b8c
+//	└── [2] 3e5a7c9d
+func (t *Tree) String() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.levels) == 0 {
+		return fmt.Sprintf("(empty) %s\n", shortHex(t.root()))
+	}
+	if n := len(t.levels[0]); n > maxPrintLeaves {
+		return fmt.Sprintf("merkle tree of %v leaves, depth %v, root %s\n", n, len(t.levels)-1, shortHex(t.root()))
+	}
+
+	var b strings.Builder
+	t.print(&b, len(t.levels)-1, 0, "", "")
+	return b.String()
+}
+
+// print writes the node at index i of level k and its subtree, the first line being prefixed
+// with head and the following ones with indent.
+func (t *Tree) print(b *strings.Builder, k, i int, head, indent string) {
+	// A node carried up is the same node as its only child, draw it once.
+	for k > 0 && 2*i+1 >= len(t.levels[k-1]) {
+		k--
+		i *= 2
+	}
+
+	node := shortHex(t.levels[k][i])
+	if k == 0 {
+		fmt.Fprintf(b, "%s[%v] %s\n", head, i, node)
+		return
+	}
+	fmt.Fprintf(b, "%s%s\n", head, node)
+	t.print(b, k-1, 2*i, indent+"├── ", indent+"│   ")
+	t.print(b, k-1, 2*i+1, indent+"└── ", indent+"    ")
+}
+
+func shortHex(h []byte) string {
+	s := hexify(h)
+	if len(s) > printHashLen {
+		return s[:printHashLen]
+	}
+	return s
+}
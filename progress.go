@@ -0,0 +1,93 @@
+package merkle
+
+import "context"
+
+// defaultProgressInterval bounds the number of hashes between two progress reports when no
+// interval is set with WithProgressInterval, reports otherwise coming every 1% of the hashes.
+const defaultProgressInterval = 1 << 16
+
+// WithProgress calls fn while Root, NewTree, ProofAll, their Ctx variants and the other functions
+// building a tree hash, with the number of hashes done and the total number of hashes of the
+// operation, 2n-1 for n items, the last call being made with done equal to total. fn is called from the goroutine running the operation and
+// never concurrently for one operation, but operations running concurrently on a Hasher call it
+// concurrently.
+// fn is called between two hashes, with no lock held and no state of the Hasher in use, so a
+// panic in fn unwinds out of the operation which returns nothing and leaves the Hasher usable.
+func WithProgress(fn func(done, total int)) Option {
+	return func(h *Hasher) {
+		h.progress = fn
+	}
+}
+
+// WithProgressInterval sets the number of hashes between two calls of the function set by WithProgress,
+// which defaults to 1% of the hashes of the operation and at most 65536 hashes.
+func WithProgressInterval(n int) Option {
+	return func(h *Hasher) {
+		h.progressEvery = n
+	}
+}
+
+// progress counts the hashes of an operation, checking its context every ctxCheckInterval hashes
+// and reporting to the Hasher's progress function.
+type progress struct {
+	ctx         context.Context
+	fn          func(done, total int)
+	done, total int
+	every       int // hashes between two calls of fn
+	check       int // done count at which ctx is next checked
+	report      int // done count at which fn is next called
+	next        int // smallest of check and report
+}
+
+func (h *Hasher) newProgress(ctx context.Context, total int) *progress {
+	p := &progress{ctx: ctx, fn: h.progress, total: total, check: ctxCheckInterval}
+	if p.fn != nil {
+		p.every = h.progressEvery
+		if p.every <= 0 {
+			p.every = (total + 99) / 100
+			if p.every > defaultProgressInterval {
+				p.every = defaultProgressInterval
+			}
+		}
+		if p.every < 1 {
+			p.every = 1
+		}
+		p.report = p.every
+	}
+	p.schedule()
+	return p
+}
+
+// step counts one hash, it returns ctx.Err() once the context is done.
+func (p *progress) step() error {
+	p.done++
+	if p.done < p.next {
+		return nil
+	}
+	if p.done >= p.check {
+		if err := p.ctx.Err(); err != nil {
+			return err
+		}
+		p.check = p.done + ctxCheckInterval
+	}
+	if p.fn != nil && p.done >= p.report {
+		p.fn(p.done, p.total)
+		p.report = p.done + p.every
+	}
+	p.schedule()
+	return nil
+}
+
+func (p *progress) schedule() {
+	p.next = p.check
+	if p.fn == nil {
+		return
+	}
+	// The last hash is always reported.
+	if p.report > p.total && p.done < p.total {
+		p.report = p.total
+	}
+	if p.report < p.next {
+		p.next = p.report
+	}
+}
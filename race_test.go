@@ -0,0 +1,7 @@
+//go:build race
+
+package merkle
+
+// raceEnabled tells that the tests run with the race detector, under which sync.Pool drops items
+// at random and the pooled hash states are allocated again.
+const raceEnabled = true
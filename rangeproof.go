@@ -0,0 +1,90 @@
+package merkle
+
+import "fmt"
+
+// RangeProof proves the inclusion of a contiguous run of leaves.
+// Hashes holds the roots of the subtrees that do not overlap the run, ordered as they are
+// met by a left to right depth first walk of the tree; subtrees inside the run are rebuilt
+// from its leaves, so a run covering the whole tree needs no hash at all.
+type RangeProof struct {
+	TreeSize int
+	Hashes   [][]byte
+}
+
+// NewRangeProof returns the proof of the items in the half open range [i, j).
+// This errors with ErrIndexOutOfBounds unless 0 <= i < j <= len(items).
+func NewRangeProof(items [][]byte, i, j int) (*RangeProof, error) {
+	return defaultHasher.NewRangeProof(items, i, j)
+}
+
+// NewRangeProof returns the proof of the items in the half open range [i, j)
+// using the Hasher's hash function.
+func (h *Hasher) NewRangeProof(items [][]byte, i, j int) (*RangeProof, error) {
+	if i < 0 || j > len(items) || i >= j {
+		return nil, fmt.Errorf("%w: range [%v, %v), tree has %v items", ErrIndexOutOfBounds, i, j, len(items))
+	}
+
+	proof := &RangeProof{TreeSize: len(items), Hashes: [][]byte{}}
+	var walk func(items [][]byte, offset int)
+	walk = func(items [][]byte, offset int) {
+		end := offset + len(items)
+		switch {
+		case end <= i || offset >= j:
+			proof.Hashes = append(proof.Hashes, h.rootAt(offset, items))
+		case offset >= i && end <= j:
+		default:
+			k := prevPowerOfTwo(len(items))
+			walk(items[:k], offset)
+			walk(items[k:], offset+k)
+		}
+	}
+	walk(items, 0)
+	return proof, nil
+}
+
+// VerifyRange verifies that leaves are the items of the tree whose root is root,
+// starting at index start.
+func VerifyRange(root []byte, start int, leaves [][]byte, proof *RangeProof) bool {
+	return defaultHasher.VerifyRange(root, start, leaves, proof)
+}
+
+// VerifyRange verifies a RangeProof using the Hasher's hash function.
+func (h *Hasher) VerifyRange(root []byte, start int, leaves [][]byte, proof *RangeProof) bool {
+	if proof == nil || start < 0 || len(leaves) == 0 || start+len(leaves) > proof.TreeSize {
+		return false
+	}
+	size := h.Size()
+	for _, p := range proof.Hashes {
+		if len(p) != size {
+			return false
+		}
+	}
+
+	end := start + len(leaves)
+	hashes := proof.Hashes
+	var walk func(offset, n int) []byte
+	walk = func(offset, n int) []byte {
+		switch {
+		case offset+n <= start || offset >= end:
+			if len(hashes) == 0 {
+				return nil
+			}
+			node := hashes[0]
+			hashes = hashes[1:]
+			return node
+		case offset >= start && offset+n <= end:
+			return h.rootAt(offset, leaves[offset-start:offset-start+n])
+		default:
+			k := prevPowerOfTwo(n)
+			left := walk(offset, k)
+			right := walk(offset+k, n-k)
+			if left == nil || right == nil {
+				return nil
+			}
+			return h.NodeHash(left, right)
+		}
+	}
+
+	computed := walk(0, proof.TreeSize)
+	return computed != nil && len(hashes) == 0 && equalDigest(root, computed)
+}
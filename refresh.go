@@ -0,0 +1,93 @@
+package merkle
+
+import "fmt"
+
+// When a tree grows from oldSize to newSize leaves, the audit path of a leaf only changes on its
+// right: the siblings on its left are perfect subtrees of earlier leaves, and so are the siblings
+// on its right that were already complete at oldSize. The other siblings on its right, which were
+// incomplete or missing, make up the delta that refreshes the proof.
+
+// ProofDelta returns the entries of the audit path of the leaf at index i in the tree of newSize
+// leaves that are not in its audit path in the tree of oldSize leaves, from the leaves up.
+// The tree must have at least newSize leaves, the sizes refer to its prefixes.
+// This errors with ErrIndexOutOfBounds when i is not below oldSize or the sizes are not ordered
+// within the size of the tree.
+func (t *Tree) ProofDelta(i, oldSize, newSize int) ([]AuditHash, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if oldSize > newSize || newSize > t.leafCount() {
+		return nil, fmt.Errorf("%w: old size %v, new size %v, tree has %v items", ErrIndexOutOfBounds, oldSize, newSize, t.leafCount())
+	}
+	if i < 0 || i >= oldSize {
+		return nil, indexError(i, oldSize)
+	}
+
+	delta := []AuditHash{}
+	walkPath(i, newSize, func(level, node, sibling int) {
+		if !unchangedSibling(level, node, sibling, oldSize, newSize) {
+			delta = append(delta, AuditHash{t.nodeAt(level, sibling, newSize), true})
+		}
+	})
+	return delta, nil
+}
+
+// unchangedSibling reports whether a sibling on the path of node is the same in the trees of
+// oldSize and newSize leaves, which holds unless it is on the right and holds leaves past oldSize.
+func unchangedSibling(level, node, sibling, oldSize, newSize int) bool {
+	return sibling < node || (sibling+1)<<uint(level) <= oldSize || oldSize == newSize
+}
+
+// nodeAt returns the node at the given level and index of the tree over the first size leaves.
+func (t *Tree) nodeAt(level, index, size int) []byte {
+	if (index+1)<<uint(level) <= size || level == 0 {
+		// A complete subtree, or a leaf, is the same in every prefix holding it.
+		return t.levels[level][index]
+	}
+	left := t.nodeAt(level-1, 2*index, size)
+	if (2*index+1)<<uint(level-1) >= size {
+		return left
+	}
+	return t.h.NodeHash(left, t.nodeAt(level-1, 2*index+1, size))
+}
+
+// RefreshProof merges the delta returned by ProofDelta into an inclusion proof for a tree of
+// old.TreeSize leaves and returns the inclusion proof of the same leaf in the tree of newSize leaves.
+// This errors with ErrIndexOutOfBounds when the sizes are not ordered or the index is out of bounds
+// and with ErrBadPathLength when the proof or the delta do not have the expected length.
+func RefreshProof(old InclusionProof, delta []AuditHash, newSize int) (InclusionProof, error) {
+	i, oldSize := old.LeafIndex, old.TreeSize
+	if oldSize > newSize {
+		return InclusionProof{}, fmt.Errorf("%w: old size %v, new size %v", ErrIndexOutOfBounds, oldSize, newSize)
+	}
+	if i < 0 || i >= oldSize {
+		return InclusionProof{}, indexError(i, oldSize)
+	}
+	if want := ProofLen(i, oldSize); len(old.Path) != want {
+		return InclusionProof{}, fmt.Errorf("%w: %v entries for index %v of %v, expected %v", ErrBadPathLength, len(old.Path), i, oldSize, want)
+	}
+
+	kept := make(map[int]AuditHash, len(old.Path))
+	j := 0
+	walkPath(i, oldSize, func(level, node, sibling int) {
+		kept[level] = old.Path[j]
+		j++
+	})
+
+	path := make([]AuditHash, 0, ProofLen(i, newSize))
+	used := 0
+	walkPath(i, newSize, func(level, node, sibling int) {
+		if unchangedSibling(level, node, sibling, oldSize, newSize) {
+			path = append(path, kept[level])
+			return
+		}
+		if used < len(delta) {
+			path = append(path, delta[used])
+		}
+		used++
+	})
+	if used != len(delta) {
+		return InclusionProof{}, fmt.Errorf("%w: delta has %v entries, expected %v", ErrBadPathLength, len(delta), used)
+	}
+	return InclusionProof{LeafIndex: i, TreeSize: newSize, Path: path}, nil
+}
@@ -0,0 +1,144 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// The test vectors of RFC 6962 trees used by the Certificate Transparency implementations: the
+// leaves, the roots of the trees over their first n leaves and some audit paths and consistency
+// proofs.
+var rfc6962Leaves = []string{"", "00", "10", "2021", "3031", "40414243", "5051525354555657", "606162636465666768696a6b6c6d6e6f"}
+
+var rfc6962Roots = []string{
+	"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+	"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+	"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+	"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+	"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+	"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+	"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+	"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+}
+
+var rfc6962InclusionProofs = []struct {
+	index, size int
+	path        []string
+}{
+	{0, 1, nil},
+	{0, 8, []string{
+		"96a296d224f285c67bee93c30f8a309157f0daa35dc5b87e410b78630a09cfc7",
+		"5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+		"6b47aaf29ee3c2af9af889bc1fb9254dabd31177f16232dd6aab035ca39bf6e4",
+	}},
+	{5, 8, []string{
+		"bc1a0643b12e4d2d7c77918f44e0f4f79a838b6cf9ec5b5c283e1f4d88599e6b",
+		"ca854ea128ed050b41b35ffc1b87b8eb2bde461e9e3b5596ece6b9d5975a0ae0",
+		"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+	}},
+	{2, 3, []string{
+		"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+	}},
+	{1, 5, []string{
+		"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		"5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+		"bc1a0643b12e4d2d7c77918f44e0f4f79a838b6cf9ec5b5c283e1f4d88599e6b",
+	}},
+}
+
+var rfc6962ConsistencyProofs = []struct {
+	oldSize, newSize int
+	proof            []string
+}{
+	{1, 1, nil},
+	{1, 8, []string{
+		"96a296d224f285c67bee93c30f8a309157f0daa35dc5b87e410b78630a09cfc7",
+		"5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+		"6b47aaf29ee3c2af9af889bc1fb9254dabd31177f16232dd6aab035ca39bf6e4",
+	}},
+	{6, 8, []string{
+		"0ebc5d3437fbe2db158b9f126a1d118e308181031d0a949f8dededebc558ef6a",
+		"ca854ea128ed050b41b35ffc1b87b8eb2bde461e9e3b5596ece6b9d5975a0ae0",
+		"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+	}},
+	{2, 5, []string{
+		"5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+		"bc1a0643b12e4d2d7c77918f44e0f4f79a838b6cf9ec5b5c283e1f4d88599e6b",
+	}},
+}
+
+func mustHex(t testing.TB, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func rfc6962Items(t testing.TB) [][]byte {
+	items := make([][]byte, len(rfc6962Leaves))
+	for i, leaf := range rfc6962Leaves {
+		items[i] = mustHex(t, leaf)
+	}
+	return items
+}
+
+func checkVectorPath(t *testing.T, what string, got []AuditHash, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%v has %v entries, want %v", what, len(got), len(want))
+		return
+	}
+	for j, entry := range got {
+		if hexify(entry.Val) != want[j] {
+			t.Errorf("%v entry %v = %x, want %v", what, j, entry.Val, want[j])
+		}
+	}
+}
+
+func TestRFC6962Roots(t *testing.T) {
+	h := NewHasher(WithSHA256())
+	items := rfc6962Items(t)
+	for n, want := range rfc6962Roots {
+		if got := hexify(h.Root(items[:n])); got != want {
+			t.Errorf("root of %v leaves = %v, want %v", n, got, want)
+		}
+		if got := hexify(h.NewTree(items[:n]).Root()); got != want {
+			t.Errorf("Tree root of %v leaves = %v, want %v", n, got, want)
+		}
+	}
+}
+
+func TestRFC6962InclusionProofs(t *testing.T) {
+	h := NewHasher(WithSHA256())
+	items := rfc6962Items(t)
+	for _, c := range rfc6962InclusionProofs {
+		p, err := h.Prove(items[:c.size], c.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkVectorPath(t, "inclusion proof", p.Path, c.path)
+		root := mustHex(t, rfc6962Roots[c.size])
+		if err := h.VerifyInclusion(root, items[c.index], p); err != nil {
+			t.Errorf("proof of %v in %v leaves: %v", c.index, c.size, err)
+		}
+	}
+}
+
+func TestRFC6962ConsistencyProofs(t *testing.T) {
+	h := NewHasher(WithSHA256())
+	items := rfc6962Items(t)
+	for _, c := range rfc6962ConsistencyProofs {
+		proof, err := h.ConsistencyProof(items[:c.newSize], c.oldSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkVectorPath(t, "consistency proof", proof, c.proof)
+		oldRoot, newRoot := mustHex(t, rfc6962Roots[c.oldSize]), mustHex(t, rfc6962Roots[c.newSize])
+		if !h.VerifyConsistency(oldRoot, newRoot, c.oldSize, c.newSize, proof) {
+			t.Errorf("consistency of %v and %v leaves does not verify", c.oldSize, c.newSize)
+		}
+	}
+}
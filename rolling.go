@@ -0,0 +1,60 @@
+package merkle
+
+import "sync"
+
+// RollingRoot is a Frontier that returns the new root after every append, O(log n) hashes each,
+// and optionally hands it to a callback, for example to publish it. It is safe for concurrent use.
+type RollingRoot struct {
+	mu     sync.Mutex
+	f      *Frontier
+	onRoot func(size int, root []byte)
+}
+
+// NewRollingRoot returns the rolling root of an empty tree, the options configure the hash
+// function as for NewHasher.
+func NewRollingRoot(opts ...Option) *RollingRoot {
+	return NewHasher(opts...).NewRollingRoot()
+}
+
+// NewRollingRoot returns the rolling root of an empty tree using the Hasher's hash function.
+func (h *Hasher) NewRollingRoot() *RollingRoot {
+	return &RollingRoot{f: h.NewFrontier()}
+}
+
+// OnRoot sets the callback called after each append with the size of the tree and its root,
+// nil removing it. The callback runs before Append returns and in the order of the appends, so a
+// slow callback slows the appends down. It must not call the methods of the RollingRoot.
+// The root it receives is its own copy.
+func (r *RollingRoot) OnRoot(fn func(size int, root []byte)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onRoot = fn
+}
+
+// Append adds a leaf at the end of the tree and returns the new root, which is the Root of the
+// leaves appended so far.
+func (r *RollingRoot) Append(leaf []byte) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.f.Append(leaf)
+	root := r.f.Root()
+	if r.onRoot != nil {
+		r.onRoot(r.f.size, append([]byte(nil), root...))
+	}
+	return append([]byte(nil), root...)
+}
+
+// Root returns the root hash of the tree over the appended leaves.
+func (r *RollingRoot) Root() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]byte(nil), r.f.Root()...)
+}
+
+// Size returns the number of leaves appended.
+func (r *RollingRoot) Size() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.size
+}
@@ -0,0 +1,106 @@
+package merkle
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// SaltSize is the size in bytes of the salts of salted trees.
+const SaltSize = 32
+
+// In a salted tree every item is hashed as LeafHash(salt || item) with its own salt, so that
+// revealing a leaf and its audit path does not let anyone brute force the other low entropy
+// leaves from the hashes of the path. The salt of a revealed leaf is handed out with its proof.
+
+// NewSaltedTree builds a tree over the items salted with random salts read from crypto/rand,
+// which are returned so that the proofs of the items can be handed out with their salt.
+// The options configure the hash function as for NewHasher.
+func NewSaltedTree(items [][]byte, opts ...Option) (*Tree, [][]byte, error) {
+	salts := make([][]byte, len(items))
+	for i := range salts {
+		salts[i] = make([]byte, SaltSize)
+		if _, err := rand.Read(salts[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return NewHasher(opts...).NewTree(saltItems(items, salts)), salts, nil
+}
+
+// NewSecretSaltedTree builds a tree over the items salted with salts derived from secret by
+// DeriveSalt, so that the prover only has to keep the secret.
+// The options configure the hash function as for NewHasher.
+func NewSecretSaltedTree(items [][]byte, secret []byte, opts ...Option) *Tree {
+	salts := make([][]byte, len(items))
+	for i := range salts {
+		salts[i] = DeriveSalt(secret, i)
+	}
+	return NewHasher(opts...).NewTree(saltItems(items, salts))
+}
+
+// DeriveSalt returns the salt of the item at index, HMAC-SHA256(secret, uint64 big endian index).
+func DeriveSalt(secret []byte, index int) []byte {
+	mac := hmac.New(sha256.New, secret)
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(index))
+	mac.Write(buf[:])
+	return mac.Sum(nil)
+}
+
+// saltItems prefixes each item with its salt.
+func saltItems(items, salts [][]byte) [][]byte {
+	salted := make([][]byte, len(items))
+	for i, item := range items {
+		salted[i] = append(append(make([]byte, 0, len(salts[i])+len(item)), salts[i]...), item...)
+	}
+	return salted
+}
+
+// SaltedProof is the inclusion proof of a leaf of a salted tree together with the salt of the leaf.
+type SaltedProof struct {
+	Salt []byte
+	InclusionProof
+}
+
+// ProveSalted returns the inclusion proof of the leaf at index i of a salted tree with the given salt.
+// This errors like Tree.Proof.
+func ProveSalted(t *Tree, i int, salt []byte) (SaltedProof, error) {
+	path, err := t.Proof(i)
+	if err != nil {
+		return SaltedProof{}, err
+	}
+	return SaltedProof{
+		Salt:           salt,
+		InclusionProof: InclusionProof{LeafIndex: i, TreeSize: t.LeafCount(), Path: path},
+	}, nil
+}
+
+// Verify verifies that leaf is included in the salted tree whose root is root.
+// This errors with ErrMalformedProof when the salt does not have SaltSize bytes
+// and otherwise like InclusionProof.Verify.
+func (p SaltedProof) Verify(root, leaf []byte) error {
+	return defaultHasher.VerifySaltedProof(root, leaf, p)
+}
+
+// VerifySaltedProof verifies a SaltedProof using the Hasher's hash function.
+func (h *Hasher) VerifySaltedProof(root, leaf []byte, p SaltedProof) error {
+	if len(p.Salt) != SaltSize {
+		return fmt.Errorf("%w: salt of %v bytes", ErrMalformedProof, len(p.Salt))
+	}
+	return h.VerifyInclusion(root, saltItems([][]byte{leaf}, [][]byte{p.Salt})[0], p.InclusionProof)
+}
+
+// VerifySalted verifies that leaf, salted with salt, is included at index in the salted tree whose root is root.
+func VerifySalted(root, leaf, salt []byte, index int, path []AuditHash) bool {
+	return defaultHasher.VerifySalted(root, leaf, salt, index, path)
+}
+
+// VerifySalted verifies a salted leaf using the Hasher's hash function.
+func (h *Hasher) VerifySalted(root, leaf, salt []byte, index int, path []AuditHash) bool {
+	if len(salt) != SaltSize {
+		return false
+	}
+	return h.VerifyProof(root, saltItems([][]byte{leaf}, [][]byte{salt})[0], index, path)
+}
@@ -0,0 +1,64 @@
+package merkle
+
+import (
+	"bytes"
+	"sort"
+)
+
+// A set tree commits to a set of items regardless of their order and multiplicity: the leaf
+// hashes of the items, H(0x00 || item), are sorted in increasing lexicographic byte order and
+// deduplicated, and the tree is built over that canonical order like Root.
+
+// SetRoot returns the root hash of the set tree over items.
+func SetRoot(items [][]byte) []byte {
+	return defaultHasher.SetRoot(items)
+}
+
+// SetRoot returns the root hash of the set tree over items using the Hasher's hash function.
+func (h *Hasher) SetRoot(items [][]byte) []byte {
+	return h.setTree(items).Root()
+}
+
+// SetProof returns the audit path of item in the set tree over items.
+// The path does not depend on where item appears in items.
+// This errors with ErrLeafNotFound when item is not one of the items.
+func SetProof(items [][]byte, item []byte) ([]AuditHash, error) {
+	return defaultHasher.SetProof(items, item)
+}
+
+// SetProof returns the audit path of item in the set tree over items using the Hasher's hash function.
+func (h *Hasher) SetProof(items [][]byte, item []byte) ([]AuditHash, error) {
+	t := h.setTree(items)
+	indices := t.indicesOf(string(h.LeafHash(item)))
+	if len(indices) == 0 {
+		return nil, ErrLeafNotFound
+	}
+	return t.proof(indices[0])
+}
+
+// SetVerify verifies that item is a member of the set whose set tree has the given root.
+func SetVerify(root, item []byte, path []AuditHash) bool {
+	return defaultHasher.SetVerify(root, item, path)
+}
+
+// SetVerify verifies set membership using the Hasher's hash function.
+func (h *Hasher) SetVerify(root, item []byte, path []AuditHash) bool {
+	// The path alone places the leaf, the index is only checked when the path is empty.
+	return h.VerifyLeafHash(root, h.LeafHash(item), 0, path)
+}
+
+// setTree returns the tree over the sorted and deduplicated leaf hashes of items.
+func (h *Hasher) setTree(items [][]byte) *Tree {
+	level := make([][]byte, len(items))
+	for i, item := range items {
+		level[i] = h.LeafHash(item)
+	}
+	sort.Slice(level, func(i, j int) bool { return bytes.Compare(level[i], level[j]) < 0 })
+	unique := level[:0]
+	for i, leaf := range level {
+		if i == 0 || !bytes.Equal(leaf, level[i-1]) {
+			unique = append(unique, leaf)
+		}
+	}
+	return h.fromLeafHashes(unique)
+}
@@ -0,0 +1,137 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Depth returns the number of levels above the leaves in a tree of n items,
+// which is the length of the longest audit path.
+func Depth(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// ProofLen returns the number of entries in the audit path of index i in a tree of n items.
+// Paths are shorter than Depth(n) for the items on the right edge of an unbalanced tree,
+// d6 has 2 entries in a tree of 7 items while d0 has 3.
+// It returns -1 when i is not an index of the tree so that no path ever has that length.
+func ProofLen(i, n int) int {
+	if i < 0 || i >= n {
+		return -1
+	}
+	length := 0
+	walkPath(i, n, func(level, node, sibling int) {
+		length++
+	})
+	return length
+}
+
+// EstimateProofSize returns the size in bytes of the audit path of index in a tree of treeSize
+// items encoded by MarshalProof, for hashes of hashSize bytes.
+// It returns -1 when index is not an index of the tree.
+func EstimateProofSize(index, treeSize int, hashSize int) int {
+	length := ProofLen(index, treeSize)
+	if length < 0 {
+		return -1
+	}
+	return envelopeSize + proofHeaderSize + length*(1+hashSize)
+}
+
+// EstimateProofHashes returns the number of hash computations needed to verify the audit path
+// of index in a tree of treeSize items: one for the leaf and one for each entry of the path.
+// It returns -1 when index is not an index of the tree.
+func EstimateProofHashes(index, treeSize int) int {
+	length := ProofLen(index, treeSize)
+	if length < 0 {
+		return -1
+	}
+	return 1 + length
+}
+
+// walkPath calls fn for each entry of the audit path of index i in a tree of n items, from the
+// leaves up, with the level of the entry and the indices within that level of the node on the
+// path and of its sibling. Levels are numbered as in Tree, a node without a sibling is carried
+// up unchanged and adds no entry.
+func walkPath(i, n int, fn func(level, node, sibling int)) {
+	for level := 0; n > 1; level++ {
+		if sibling := i ^ 1; sibling < n {
+			fn(level, i, sibling)
+		}
+		i /= 2
+		n = (n + 1) / 2
+	}
+}
+
+// NodeCoord locates a node of a tree by its level, 0 for the leaves, and its index within the
+// level, the node at level l and index x covering the leaves [x*2^l, min((x+1)*2^l, n)) of a tree
+// of n items as in Tree. Right tells that the node goes on the right of the concatenation when it
+// is an entry of an audit path.
+type NodeCoord struct {
+	Level, Index int
+	Right        bool
+}
+
+// PathIndices returns the coordinates of the nodes making up the audit path of index leafIndex in
+// a tree of treeSize items, in the order and with the sides of the entries of Proof, so that a
+// client fetching nodes by coordinates from a remote store knows which ones to ask for.
+// The paths of the items on the right edge of an unbalanced tree are shorter, see ProofLen.
+// This errors with ErrEmptyTree when treeSize is not positive and ErrIndexOutOfBounds when
+// leafIndex is out of bounds.
+func PathIndices(leafIndex, treeSize int) ([]NodeCoord, error) {
+	if treeSize <= 0 {
+		return nil, ErrEmptyTree
+	}
+	if leafIndex < 0 || leafIndex >= treeSize {
+		return nil, indexError(leafIndex, treeSize)
+	}
+	coords := make([]NodeCoord, 0, Depth(treeSize))
+	walkPath(leafIndex, treeSize, func(level, node, sibling int) {
+		coords = append(coords, NodeCoord{Level: level, Index: sibling, Right: sibling > node})
+	})
+	return coords, nil
+}
+
+// ZipPath returns the audit path made of the hashes fetched for the coordinates returned by
+// PathIndices, hashes[k] being the hash of the node at coords[k].
+// This errors with ErrBadPathLength when there is not one hash per coordinate.
+func ZipPath(coords []NodeCoord, hashes [][]byte) ([]AuditHash, error) {
+	if len(coords) != len(hashes) {
+		return nil, fmt.Errorf("%w: %v hashes for %v nodes", ErrBadPathLength, len(hashes), len(coords))
+	}
+	path := make([]AuditHash, len(coords))
+	for k, c := range coords {
+		path[k] = AuditHash{hashes[k], c.Right}
+	}
+	return path, nil
+}
+
+// LeafCount returns the number of leaves of the tree.
+func (t *Tree) LeafCount() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.leafCount()
+}
+
+func (t *Tree) leafCount() int {
+	if len(t.levels) == 0 {
+		return 0
+	}
+	return len(t.levels[0])
+}
+
+// Depth returns the number of levels above the leaves of the tree.
+func (t *Tree) Depth() int {
+	return Depth(t.LeafCount())
+}
+
+// NodeCount returns the number of nodes of the tree, leaves included.
+// A node carried up without a sibling is counted once, so a tree of n > 0 leaves has 2n-1 nodes.
+func (t *Tree) NodeCount() int {
+	if n := t.LeafCount(); n > 0 {
+		return 2*n - 1
+	}
+	return 0
+}
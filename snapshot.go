@@ -0,0 +1,126 @@
+package merkle
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// snapshotHeaderSize is the size of the fields of a snapshot before its leaf hashes.
+const snapshotHeaderSize = envelopeSize + 3 + 8
+
+// Save writes a snapshot of the tree to w in the envelope of the package with the following payload:
+//
+//	uint8   HashID of the hash function
+//	uint8   digest size
+//	uint64  number of leaves, big endian
+//	leaf hashes, in order
+//	root hash
+//	SHA-256 checksum of everything above
+//
+// Only the leaf hashes are stored, the interior nodes are rebuilt from them when loading,
+// which hashes two digests per node instead of the items.
+func (t *Tree) Save(w io.Writer) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	size := t.h.Size()
+	if size == 0 || size > maxHashSize {
+		return fmt.Errorf("%w: unsupported hash size %v", ErrInvalidSnapshot, size)
+	}
+
+	checksum := sha256.New()
+	bw := bufio.NewWriter(io.MultiWriter(w, checksum))
+
+	var header [snapshotHeaderSize]byte
+	envelope := envelopeHeader(kindSnapshot)
+	copy(header[:], envelope[:])
+	header[envelopeSize] = byte(t.h.id)
+	header[envelopeSize+1] = byte(size)
+	var leaves [][]byte
+	if len(t.levels) > 0 {
+		leaves = t.levels[0]
+	}
+	binary.BigEndian.PutUint64(header[envelopeSize+2:], uint64(len(leaves)))
+	bw.Write(header[:])
+	for _, leaf := range leaves {
+		bw.Write(leaf)
+	}
+	bw.Write(t.root())
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(checksum.Sum(nil))
+	return err
+}
+
+// LoadTree reads a tree saved by Save, the options configure the hash function as for NewHasher
+// and must match the one the tree was saved with.
+// The checksum is verified and the root rebuilt from the leaf hashes is compared with the saved
+// root before the tree is returned.
+// This errors with ErrUnknownFormat or ErrUnknownFormatVersion when the data is not a snapshot of
+// this format version, with ErrHashMismatch when the snapshot uses another hash function and with
+// ErrInvalidSnapshot when it is truncated or inconsistent.
+func LoadTree(r io.Reader, opts ...Option) (*Tree, error) {
+	return NewHasher(opts...).LoadTree(r)
+}
+
+// LoadTree reads a tree saved by Save using the Hasher's hash function.
+func (h *Hasher) LoadTree(r io.Reader) (*Tree, error) {
+	checksum := sha256.New()
+	tr := io.TeeReader(bufio.NewReader(r), checksum)
+
+	var envelope [envelopeSize]byte
+	if _, err := io.ReadFull(tr, envelope[:]); err != nil {
+		return nil, fmt.Errorf("%w: truncated envelope", ErrUnknownFormat)
+	}
+	if err := checkEnvelope(kindSnapshot, envelope[:]); err != nil {
+		return nil, err
+	}
+
+	var header [snapshotHeaderSize - envelopeSize]byte
+	if _, err := io.ReadFull(tr, header[:]); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidSnapshot, err)
+	}
+	if id := HashID(header[0]); id != h.id {
+		return nil, fmt.Errorf("%w: snapshot uses hash %v, expected %v", ErrHashMismatch, id, h.id)
+	}
+	size := int(header[1])
+	if size != h.Size() {
+		return nil, fmt.Errorf("%w: hash size %v, expected %v", ErrInvalidSnapshot, size, h.Size())
+	}
+	count := binary.BigEndian.Uint64(header[2:])
+
+	// The leaves are read one at a time so that a bogus count cannot make us allocate
+	// more than the data actually holds.
+	var leaves [][]byte
+	for i := uint64(0); i < count; i++ {
+		leaf := make([]byte, size)
+		if _, err := io.ReadFull(tr, leaf); err != nil {
+			return nil, fmt.Errorf("%w: leaf %v: %v", ErrInvalidSnapshot, i, err)
+		}
+		leaves = append(leaves, leaf)
+	}
+	root := make([]byte, size)
+	if _, err := io.ReadFull(tr, root); err != nil {
+		return nil, fmt.Errorf("%w: root: %v", ErrInvalidSnapshot, err)
+	}
+
+	sum := checksum.Sum(nil)
+	saved := make([]byte, len(sum))
+	if _, err := io.ReadFull(tr, saved); err != nil {
+		return nil, fmt.Errorf("%w: checksum: %v", ErrInvalidSnapshot, err)
+	}
+	if !equalDigest(sum, saved) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidSnapshot)
+	}
+
+	t := h.fromLeafHashes(leaves)
+	if !equalDigest(t.root(), root) {
+		return nil, fmt.Errorf("%w: root mismatch", ErrInvalidSnapshot)
+	}
+	return t, nil
+}
@@ -0,0 +1,38 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	tree := NewTree(testItems(7))
+	var buf bytes.Buffer
+	if err := tree.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	loaded, err := LoadTree(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalDigest(loaded.Root(), tree.Root()) {
+		t.Fatal("loaded tree has another root")
+	}
+
+	// The snapshots without the envelope, starting with "MRKT" and the version 1, are not read.
+	old := append([]byte("MRKT\x01"), data[envelopeSize:len(data)-sha256.Size]...)
+	sum := sha256.Sum256(old)
+	old = append(old, sum[:]...)
+	if _, err := LoadTree(bytes.NewReader(old)); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("MRKT snapshot: got %v, want ErrUnknownFormat", err)
+	}
+	if _, err := LoadTree(bytes.NewReader(data[:3])); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("truncated envelope: got %v, want ErrUnknownFormat", err)
+	}
+	if _, err := LoadTree(bytes.NewReader(data[:len(data)-1])); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("truncated checksum: got %v, want ErrInvalidSnapshot", err)
+	}
+}
@@ -0,0 +1,112 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// maxSolidityPath is the number of entries whose directions fit in a uint256 bitmap.
+const maxSolidityPath = 256
+
+// EncodeSolidityProof returns the entries of an audit path of 32 byte digests as a Solidity
+// bytes32[] and their directions as a uint256 bitmap, bit l being set when entry l goes on the
+// left, which is when the node reached so far is a right child. A contract folds them with:
+//
+//	for (uint256 i = 0; i < proof.length; i++) {
+//	    node = (directions >> i) & 1 == 1
+//	        ? keccak256(abi.encodePacked(bytes1(0x01), proof[i], node))
+//	        : keccak256(abi.encodePacked(bytes1(0x01), node, proof[i]));
+//	}
+//
+// with the interior prefix and hash function of the Hasher that built the tree, WithKeccak256
+// here. This errors with ErrBadHashSize when an entry is not 32 bytes and with ErrPathTooLong
+// when the path has more than 256 entries.
+func EncodeSolidityProof(path []AuditHash) ([][32]byte, *big.Int, error) {
+	if len(path) > maxSolidityPath {
+		return nil, nil, fmt.Errorf("%w: %v entries, a uint256 bitmap holds %v", ErrPathTooLong, len(path), maxSolidityPath)
+	}
+	hashes := make([][32]byte, len(path))
+	directions := new(big.Int)
+	for l, p := range path {
+		if len(p.Val) != 32 {
+			return nil, nil, fmt.Errorf("%w: entry %v has size %v, expected 32", ErrBadHashSize, l, len(p.Val))
+		}
+		copy(hashes[l][:], p.Val)
+		if !p.RightOperator {
+			directions.SetBit(directions, l, 1)
+		}
+	}
+	return hashes, directions, nil
+}
+
+// DecodeSolidityProof returns the audit path encoded by EncodeSolidityProof.
+// A nil directions has no bit set.
+// This errors with ErrPathTooLong when there are more than 256 hashes and with ErrMalformedProof
+// when directions is negative or has bits set past the last hash.
+func DecodeSolidityProof(hashes [][32]byte, directions *big.Int) ([]AuditHash, error) {
+	if len(hashes) > maxSolidityPath {
+		return nil, fmt.Errorf("%w: %v entries, a uint256 bitmap holds %v", ErrPathTooLong, len(hashes), maxSolidityPath)
+	}
+	if directions == nil {
+		directions = new(big.Int)
+	}
+	if directions.Sign() < 0 || directions.BitLen() > len(hashes) {
+		return nil, fmt.Errorf("%w: directions %v for %v entries", ErrMalformedProof, directions, len(hashes))
+	}
+	path := make([]AuditHash, len(hashes))
+	for l := range hashes {
+		path[l] = AuditHash{append([]byte(nil), hashes[l][:]...), directions.Bit(l) == 0}
+	}
+	return path, nil
+}
+
+// EncodeSolidityProofABI returns the ABI encoding of the arguments (bytes32[] proof, uint256 directions)
+// of EncodeSolidityProof, the calldata of a call taking them after its 4 byte selector.
+// This errors like EncodeSolidityProof.
+func EncodeSolidityProofABI(path []AuditHash) ([]byte, error) {
+	hashes, directions, err := EncodeSolidityProof(path)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 3*32, (3+len(hashes))*32)
+	binary.BigEndian.PutUint64(data[24:32], 2*32) // offset of the array after the two head words
+	directions.FillBytes(data[32:64])
+	binary.BigEndian.PutUint64(data[88:96], uint64(len(hashes)))
+	for _, hash := range hashes {
+		data = append(data, hash[:]...)
+	}
+	return data, nil
+}
+
+// DecodeSolidityProofABI returns the audit path encoded by EncodeSolidityProofABI.
+// This errors with ErrMalformedProof when data is not the canonical encoding of such a path,
+// and like DecodeSolidityProof.
+func DecodeSolidityProofABI(data []byte) ([]AuditHash, error) {
+	if len(data) < 3*32 || len(data)%32 != 0 {
+		return nil, fmt.Errorf("%w: %v bytes of calldata", ErrMalformedProof, len(data))
+	}
+	offset, ok := abiUint(data[0:32])
+	if !ok || offset != 2*32 {
+		return nil, fmt.Errorf("%w: proof array at an unexpected offset", ErrMalformedProof)
+	}
+	n, ok := abiUint(data[64:96])
+	if !ok || n != uint64(len(data)/32-3) {
+		return nil, fmt.Errorf("%w: proof array length does not match %v bytes of calldata", ErrMalformedProof, len(data))
+	}
+	hashes := make([][32]byte, n)
+	for i := range hashes {
+		copy(hashes[i][:], data[(3+i)*32:])
+	}
+	return DecodeSolidityProof(hashes, new(big.Int).SetBytes(data[32:64]))
+}
+
+// abiUint returns the value of a 32 byte ABI word that fits in a uint64.
+func abiUint(word []byte) (uint64, bool) {
+	for _, b := range word[:24] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	return binary.BigEndian.Uint64(word[24:]), true
+}
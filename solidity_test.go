@@ -0,0 +1,84 @@
+package merkle
+
+import (
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// solidityItems are the items of the tree of testdata/solidity_proof.golden, which holds the
+// calldata of the proof of the item at index 4 as computed by an independent implementation of
+// keccak256 and abi.encode.
+var solidityItems = [][]byte{[]byte("alice"), []byte("bob"), []byte("carol"), []byte("dave"), []byte("erin"), []byte("frank")}
+
+const solidityRoot = "8f5b815b50b4c82d1688a629caa1f7b47796e37d022f80fd0f88d657c3376a30"
+
+// solidityVerify is MerkleVerifier.verify of testdata/MerkleVerifier.sol.
+func solidityVerify(root [32]byte, item []byte, proof [][32]byte, directions *big.Int) bool {
+	keccak := func(parts ...[]byte) []byte {
+		d := sha3.NewLegacyKeccak256()
+		for _, p := range parts {
+			d.Write(p)
+		}
+		return d.Sum(nil)
+	}
+	node := keccak([]byte{0x00}, item)
+	for i := range proof {
+		if directions.Bit(i) == 1 {
+			node = keccak([]byte{0x01}, proof[i][:], node)
+		} else {
+			node = keccak([]byte{0x01}, node, proof[i][:])
+		}
+	}
+	return equalDigest(node, root[:])
+}
+
+func TestSolidityCalldataGolden(t *testing.T) {
+	golden, err := os.ReadFile("testdata/solidity_proof.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.TrimSpace(string(golden))
+
+	h := NewHasher(WithKeccak256())
+	if got := hexify(h.Root(solidityItems)); got != solidityRoot {
+		t.Fatalf("root = %v, want %v", got, solidityRoot)
+	}
+	path, err := h.Proof(solidityItems, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := EncodeSolidityProofABI(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hexify(data); got != want {
+		t.Fatalf("calldata = %v, want %v", got, want)
+	}
+
+	decoded, err := DecodeSolidityProofABI(mustHex(t, want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.VerifyProof(mustHex(t, solidityRoot), solidityItems[4], 4, decoded) {
+		t.Error("the decoded golden proof does not verify")
+	}
+	var root [32]byte
+	copy(root[:], mustHex(t, solidityRoot))
+	for i, item := range solidityItems {
+		path, _ := h.Proof(solidityItems, i)
+		hashes, directions, err := EncodeSolidityProof(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !solidityVerify(root, item, hashes, directions) {
+			t.Errorf("the verifier contract rejects the proof of %v", i)
+		}
+		if solidityVerify(root, []byte("mallory"), hashes, directions) {
+			t.Errorf("the verifier contract accepts another item with the proof of %v", i)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package merkle
+
+// sortedPairsHasher hashes leaves and nodes the way OpenZeppelin's MerkleProof does:
+// keccak256 without domain separation prefixes, the smaller child being hashed first.
+var sortedPairsHasher = NewHasher(WithKeccak256(), withSortedPairs())
+
+// withSortedPairs drops the domain separation prefixes and sorts the children of every node.
+func withSortedPairs() Option {
+	return func(h *Hasher) {
+		h.leafPrefix = nil
+		h.interiorPrefix = nil
+		h.sortPairs = true
+		h.unsafePrefixes = true
+	}
+}
+
+// SortedPairsTree is a merkle tree compatible with OpenZeppelin's MerkleProof.verify and
+// merkletreejs with the sortPairs and hashLeaves options.
+// Leaves are hashed as keccak256(leaf) and interior nodes as keccak256(min(a, b) || max(a, b)),
+// a node without a sibling is carried up unchanged.
+type SortedPairsTree struct {
+	t *Tree
+}
+
+// NewSortedPairsTree hashes the items into a sorted pairs tree.
+func NewSortedPairsTree(items [][]byte) *SortedPairsTree {
+	return &SortedPairsTree{t: sortedPairsHasher.NewTree(items)}
+}
+
+// Root returns the root hash of the tree, like merkletreejs an empty tree has an empty root.
+func (s *SortedPairsTree) Root() []byte {
+	if len(s.t.levels) == 0 {
+		return []byte{}
+	}
+	return s.t.Root()
+}
+
+// Proof returns the sibling hashes from the leaf at index i up to the root.
+// As pairs are sorted before hashing the proof carries no directions.
+// This errors with ErrEmptyTree when the tree has no items and ErrIndexOutOfBounds
+// when the requested index is out of bounds.
+func (s *SortedPairsTree) Proof(i int) ([][]byte, error) {
+	path, err := s.t.Proof(i)
+	if err != nil {
+		return nil, err
+	}
+	proof := make([][]byte, len(path))
+	for j, p := range path {
+		proof[j] = p.Val
+	}
+	return proof, nil
+}
+
+// VerifySortedPairs verifies that leaf is included in the sorted pairs tree whose root is root.
+// The leaf is the raw item, the value passed to MerkleProof.verify is keccak256(leaf).
+func VerifySortedPairs(root []byte, leaf []byte, proof [][]byte) bool {
+	h := sortedPairsHasher
+	size := h.Size()
+	if len(root) != size {
+		return false
+	}
+
+	d := h.LeafHash(leaf)
+	for _, p := range proof {
+		if len(p) != size {
+			return false
+		}
+		d = h.NodeHash(d, p)
+	}
+	return equalDigest(root, d)
+}
@@ -0,0 +1,180 @@
+package merkle
+
+import "encoding/binary"
+
+// SparseTree is a sparse merkle tree committing to a key value map.
+// Its depth is the bit size of the digests, the path of a key from the root being the bits
+// of the hash of the key, most significant bit first, 0 going left and 1 going right.
+// A set key has the leaf hash of its value as leaf, the other leaves are the hash of the empty
+// string, and the roots of the subtrees holding no set key are precomputed so that an empty tree
+// costs depth hashes.
+//
+// Audit paths have exactly depth entries, ordered from the leaf up like those of Proof.
+type SparseTree struct {
+	h        *Hasher
+	depth    int
+	defaults [][]byte          // defaults[l] is the root of an empty subtree of height l
+	nodes    map[string][]byte // nodes off the default subtrees, keyed by height and path prefix
+	values   map[string][]byte
+}
+
+// NewSparseTree returns an empty sparse tree, the options configure the hash function as for NewHasher.
+func NewSparseTree(opts ...Option) *SparseTree {
+	return NewHasher(opts...).NewSparseTree()
+}
+
+// NewSparseTree returns an empty sparse tree using the Hasher's hash function.
+func (h *Hasher) NewSparseTree() *SparseTree {
+	return &SparseTree{
+		h:        h,
+		depth:    8 * h.Size(),
+		defaults: h.sparseDefaults(),
+		nodes:    make(map[string][]byte),
+		values:   make(map[string][]byte),
+	}
+}
+
+// sparseDefaults returns the roots of the empty subtrees of every height, from the leaves up.
+func (h *Hasher) sparseDefaults() [][]byte {
+	depth := 8 * h.Size()
+	defaults := make([][]byte, depth+1)
+	defaults[0] = h.emptyHash()
+	for l := 1; l <= depth; l++ {
+		defaults[l] = h.NodeHash(defaults[l-1], defaults[l-1])
+	}
+	return defaults
+}
+
+// Root returns the root hash of the tree.
+func (s *SparseTree) Root() []byte {
+	return s.node(s.depth, make([]byte, s.depth/8))
+}
+
+// Set sets the value of a key, rehashing the depth nodes on its path.
+func (s *SparseTree) Set(key, value []byte) {
+	path := s.h.hash(key)
+	s.values[string(key)] = append([]byte(nil), value...)
+
+	node := s.h.LeafHash(value)
+	s.nodes[nodeKey(0, path)] = node
+	for l := 0; l < s.depth; l++ {
+		sibling := s.node(l, flipBit(path, s.depth-1-l))
+		if bit(path, s.depth-1-l) == 0 {
+			node = s.h.NodeHash(node, sibling)
+		} else {
+			node = s.h.NodeHash(sibling, node)
+		}
+		s.nodes[nodeKey(l+1, path)] = node
+	}
+}
+
+// Get returns the value of a key and whether it is set.
+func (s *SparseTree) Get(key []byte) ([]byte, bool) {
+	value, ok := s.values[string(key)]
+	return value, ok
+}
+
+// ProveInclusion returns the audit path of a set key.
+// This errors with ErrKeyNotFound when the key is not set.
+func (s *SparseTree) ProveInclusion(key []byte) ([]AuditHash, error) {
+	if _, ok := s.values[string(key)]; !ok {
+		return nil, ErrKeyNotFound
+	}
+	return s.path(key), nil
+}
+
+// ProveExclusion returns the audit path of the empty leaf of a key that is not set.
+// This errors with ErrKeyExists when the key is set.
+func (s *SparseTree) ProveExclusion(key []byte) ([]AuditHash, error) {
+	if _, ok := s.values[string(key)]; ok {
+		return nil, ErrKeyExists
+	}
+	return s.path(key), nil
+}
+
+func (s *SparseTree) path(key []byte) []AuditHash {
+	path := s.h.hash(key)
+	res := make([]AuditHash, s.depth)
+	for l := range res {
+		sibling := s.node(l, flipBit(path, s.depth-1-l))
+		res[l] = AuditHash{sibling, bit(path, s.depth-1-l) == 0}
+	}
+	return res
+}
+
+// node returns the hash of the node of height l above path.
+func (s *SparseTree) node(l int, path []byte) []byte {
+	if node, ok := s.nodes[nodeKey(l, path)]; ok {
+		return node
+	}
+	return s.defaults[l]
+}
+
+// VerifySparseInclusion verifies that key is set to value in the sparse tree whose root is root.
+func VerifySparseInclusion(root, key, value []byte, path []AuditHash) bool {
+	return defaultHasher.VerifySparseInclusion(root, key, value, path)
+}
+
+// VerifySparseInclusion verifies a sparse tree inclusion proof using the Hasher's hash function.
+func (h *Hasher) VerifySparseInclusion(root, key, value []byte, path []AuditHash) bool {
+	return h.verifySparse(root, key, h.LeafHash(value), path)
+}
+
+// VerifySparseExclusion verifies that key is not set in the sparse tree whose root is root.
+func VerifySparseExclusion(root, key []byte, path []AuditHash) bool {
+	return defaultHasher.VerifySparseExclusion(root, key, path)
+}
+
+// VerifySparseExclusion verifies a sparse tree exclusion proof using the Hasher's hash function.
+func (h *Hasher) VerifySparseExclusion(root, key []byte, path []AuditHash) bool {
+	return h.verifySparse(root, key, h.emptyHash(), path)
+}
+
+// verifySparse folds the path from leaf, checking that its directions are the bits of the key's path.
+func (h *Hasher) verifySparse(root, key, leaf []byte, path []AuditHash) bool {
+	size := h.Size()
+	depth := 8 * size
+	if len(root) != size || len(path) != depth {
+		return false
+	}
+
+	keyPath := h.hash(key)
+	node := leaf
+	for l, p := range path {
+		if len(p.Val) != size || p.RightOperator != (bit(keyPath, depth-1-l) == 0) {
+			return false
+		}
+		if p.RightOperator {
+			node = h.NodeHash(node, p.Val)
+		} else {
+			node = h.NodeHash(p.Val, node)
+		}
+	}
+	return equalDigest(root, node)
+}
+
+// nodeKey identifies the node of height l above path, the bits below the node are cleared.
+func nodeKey(l int, path []byte) string {
+	key := make([]byte, 2+len(path))
+	binary.BigEndian.PutUint16(key, uint16(l))
+	copy(key[2:], path)
+
+	prefix := key[2:]
+	for i := 0; i < l; i++ {
+		j := len(path)*8 - 1 - i
+		prefix[j/8] &^= 0x80 >> uint(j%8)
+	}
+	return string(key)
+}
+
+// bit returns the i-th bit of path, most significant bit first.
+func bit(path []byte, i int) byte {
+	return (path[i/8] >> uint(7-i%8)) & 1
+}
+
+// flipBit returns a copy of path with the i-th bit flipped.
+func flipBit(path []byte, i int) []byte {
+	flipped := append([]byte(nil), path...)
+	flipped[i/8] ^= 0x80 >> uint(i%8)
+	return flipped
+}
@@ -0,0 +1,72 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// SubtreeRoot returns the hashes of the nodes of the tree over items that cover the leaves [i, j)
+// as they sit in that tree, rather than the root of a tree built over items[i:j]. When [i, j) is
+// exactly the range of a node, such as the halves on either side of the prevPowerOfTwo split, this
+// is the hash of that node alone; otherwise it is the fewest nodes whose ranges make up [i, j), in
+// order from left to right. Every returned hash is a node of the tree: the nodes returned for the
+// two children of a node combine with NodeHash into that node, so that, for k = prevPowerOfTwo(n),
+// NodeHash of the nodes of [0, k) and [k, n) is Root(items).
+// This errors with ErrIndexOutOfBounds when [i, j) is empty or not within the items.
+func SubtreeRoot(items [][]byte, i, j int) ([][]byte, error) {
+	return defaultHasher.SubtreeRoot(items, i, j)
+}
+
+// SubtreeRoot returns the nodes covering the leaves [i, j) of the tree over items using the
+// Hasher's hash function.
+func (h *Hasher) SubtreeRoot(items [][]byte, i, j int) ([][]byte, error) {
+	if err := checkRange(i, j, len(items)); err != nil {
+		return nil, err
+	}
+	var nodes [][]byte
+	coverRange(0, len(items), i, j, func(lo, hi int) {
+		nodes = append(nodes, h.rootAt(lo, items[lo:hi]))
+	})
+	return nodes, nil
+}
+
+// SubtreeRoot returns the nodes of the tree covering the leaves [i, j), read from the nodes it
+// keeps, see the package level SubtreeRoot.
+// This errors with ErrIndexOutOfBounds when [i, j) is empty or not within the items of the tree.
+func (t *Tree) SubtreeRoot(i, j int) ([][]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if err := checkRange(i, j, t.leafCount()); err != nil {
+		return nil, err
+	}
+	var nodes [][]byte
+	coverRange(0, t.leafCount(), i, j, func(lo, hi int) {
+		// The node of the leaves [lo, hi) is the one of its level holding lo, that level being the
+		// bit length of the power of two covering hi - lo.
+		level := bits.Len(uint(hi - lo - 1))
+		nodes = append(nodes, append([]byte(nil), t.levels[level][lo>>uint(level)]...))
+	})
+	return nodes, nil
+}
+
+func checkRange(i, j, n int) error {
+	if i < 0 || j > n || i >= j {
+		return fmt.Errorf("%w: range [%v, %v), tree has %v items", ErrIndexOutOfBounds, i, j, n)
+	}
+	return nil
+}
+
+// coverRange calls node, in order, with the ranges [lo, hi) of the largest nodes of the subtree
+// over the leaves [lo, hi) that lie within [i, j), splitting as Root does.
+func coverRange(lo, hi, i, j int, node func(lo, hi int)) {
+	switch {
+	case j <= lo || hi <= i:
+	case i <= lo && hi <= j:
+		node(lo, hi)
+	default:
+		k := lo + prevPowerOfTwo(hi-lo)
+		coverRange(lo, k, i, j, node)
+		coverRange(k, hi, i, j, node)
+	}
+}
@@ -0,0 +1,190 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// SumTree is a merkle sum tree: every leaf carries an amount and every node commits to the sum
+// of the amounts below it, so that an inclusion proof shows that the amount of a leaf is counted
+// in the total of the root, as for proofs of reserves. It has the shape of a Tree with
+//
+//	leaf   H(0x00 || amount || item)
+//	node   H(0x01 || left || right || leftSum+rightSum)
+//
+// with the prefixes of the Hasher and amounts as 8 byte big endian integers. A node without a
+// sibling is carried up unchanged. The indices of the leaves are not bound into their hashes.
+// Hashers set WithNodeHasher are not supported, their hash functions having no byte stream.
+type SumTree struct {
+	h      *Hasher
+	levels [][]sumNode
+}
+
+// sumNode is a node of a SumTree with the sum of the amounts below it.
+type sumNode struct {
+	hash []byte
+	sum  uint64
+}
+
+// SumAuditHash is an entry of the audit path of a SumTree, the hash and the sum of the sibling.
+type SumAuditHash struct {
+	Val           []byte
+	Sum           uint64
+	RightOperator bool // the sibling goes on the right of the concatenation
+}
+
+// NewSumTree hashes the items with their amounts into a sum tree, the options configure the hash
+// function as for NewHasher.
+// This errors with ErrInvalidLeaf when there is not one amount per item and with ErrSumOverflow
+// when the total does not fit in a uint64.
+func NewSumTree(items [][]byte, amounts []uint64, opts ...Option) (*SumTree, error) {
+	return NewHasher(opts...).NewSumTree(items, amounts)
+}
+
+// NewSumTree hashes the items with their amounts into a sum tree using the Hasher's hash function.
+// It panics when the Hasher was set WithNodeHasher.
+func (h *Hasher) NewSumTree(items [][]byte, amounts []uint64) (*SumTree, error) {
+	h.checkSumHasher()
+	if len(items) != len(amounts) {
+		return nil, fmt.Errorf("%w: %v items for %v amounts", ErrInvalidLeaf, len(items), len(amounts))
+	}
+	if len(items) == 0 {
+		return &SumTree{h: h}, nil
+	}
+	level := make([]sumNode, len(items))
+	for i, item := range items {
+		level[i] = sumNode{h.sumLeafHash(item, amounts[i]), amounts[i]}
+	}
+	s := &SumTree{h: h, levels: [][]sumNode{level}}
+	for len(level) > 1 {
+		next := make([]sumNode, (len(level)+1)/2)
+		for i := range next {
+			if 2*i+1 == len(level) {
+				next[i] = level[2*i]
+				continue
+			}
+			sum, carry := bits.Add64(level[2*i].sum, level[2*i+1].sum, 0)
+			if carry != 0 {
+				return nil, fmt.Errorf("%w: the total of the amounts", ErrSumOverflow)
+			}
+			next[i] = sumNode{h.sumNodeHash(level[2*i].hash, level[2*i+1].hash, sum), sum}
+		}
+		s.levels = append(s.levels, next)
+		level = next
+	}
+	return s, nil
+}
+
+// Len returns the number of items of the tree.
+func (s *SumTree) Len() int {
+	if len(s.levels) == 0 {
+		return 0
+	}
+	return len(s.levels[0])
+}
+
+// Root returns the root hash of the tree and the total of its amounts, the root of the empty
+// tree and 0 when it has no items.
+func (s *SumTree) Root() ([]byte, uint64) {
+	if len(s.levels) == 0 {
+		return s.h.emptyHash(), 0
+	}
+	root := s.levels[len(s.levels)-1][0]
+	return append([]byte(nil), root.hash...), root.sum
+}
+
+// Proof returns the audit path of the item at index i, from the leaf up, each entry with the
+// sum of its subtree. A tree of a single item has an empty path.
+// This errors with ErrEmptyTree when the tree has no items and ErrIndexOutOfBounds when the
+// index is out of bounds.
+func (s *SumTree) Proof(i int) ([]SumAuditHash, error) {
+	if s.Len() == 0 {
+		return nil, ErrEmptyTree
+	}
+	if i < 0 || i >= s.Len() {
+		return nil, indexError(i, s.Len())
+	}
+	var path []SumAuditHash
+	for _, level := range s.levels[:len(s.levels)-1] {
+		if sibling := i ^ 1; sibling < len(level) {
+			path = append(path, SumAuditHash{append([]byte(nil), level[sibling].hash...), level[sibling].sum, sibling > i})
+		}
+		i >>= 1
+	}
+	return path, nil
+}
+
+// VerifySumProof verifies that item with amount is included in the sum tree whose root hash is
+// root and whose amounts add up to total.
+// This errors with ErrBadHashSize when the root or an entry does not have the digest size,
+// ErrSumOverflow when the sums along the path overflow a uint64 and ErrRootMismatch when the path
+// does not lead to root and total.
+func VerifySumProof(root []byte, total uint64, item []byte, amount uint64, path []SumAuditHash) error {
+	return defaultHasher.VerifySumProof(root, total, item, amount, path)
+}
+
+// VerifySumProof verifies a sum tree inclusion proof using the Hasher's hash function.
+// It panics when the Hasher was set WithNodeHasher.
+func (h *Hasher) VerifySumProof(root []byte, total uint64, item []byte, amount uint64, path []SumAuditHash) error {
+	h.checkSumHasher()
+	size := h.Size()
+	if len(root) != size {
+		return fmt.Errorf("%w: root has size %v, expected %v", ErrBadHashSize, len(root), size)
+	}
+	for j, e := range path {
+		if len(e.Val) != size {
+			return fmt.Errorf("%w: entry %v has size %v, expected %v", ErrBadHashSize, j, len(e.Val), size)
+		}
+	}
+
+	node, sum := h.sumLeafHash(item, amount), amount
+	for j, e := range path {
+		var carry uint64
+		if sum, carry = bits.Add64(sum, e.Sum, 0); carry != 0 {
+			return fmt.Errorf("%w: at entry %v", ErrSumOverflow, j)
+		}
+		if e.RightOperator {
+			node = h.sumNodeHash(node, e.Val, sum)
+		} else {
+			node = h.sumNodeHash(e.Val, node, sum)
+		}
+	}
+	if sum != total {
+		return fmt.Errorf("%w: the path adds up to %v, expected %v", ErrRootMismatch, sum, total)
+	}
+	return h.matchRoot(root, node)
+}
+
+func (h *Hasher) checkSumHasher() {
+	if h.nodeHasher != nil {
+		panic("merkle: sum trees need a hash function over bytes, not a NodeHasher")
+	}
+}
+
+// sumLeafHash returns the hash of a leaf of a SumTree.
+func (h *Hasher) sumLeafHash(item []byte, amount uint64) []byte {
+	var a [8]byte
+	binary.BigEndian.PutUint64(a[:], amount)
+	d := h.acquireLeaf()
+	d.Write(h.leafPrefix)
+	d.Write(a[:])
+	h.writeLeafData(d, nil, item)
+	sum := d.Sum(nil)
+	h.leafPool.Put(d)
+	return sum
+}
+
+// sumNodeHash returns the hash of an interior node of a SumTree.
+func (h *Hasher) sumNodeHash(left, right []byte, sum uint64) []byte {
+	var s [8]byte
+	binary.BigEndian.PutUint64(s[:], sum)
+	d := h.acquire()
+	d.Write(h.interiorPrefix)
+	d.Write(left)
+	d.Write(right)
+	d.Write(s[:])
+	res := d.Sum(nil)
+	h.pool.Put(d)
+	return res
+}
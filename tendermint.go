@@ -0,0 +1,65 @@
+package merkle
+
+import "fmt"
+
+// The simple merkle trees of Tendermint and CometBFT, whose roots are for example the DataHash
+// of block headers, are RFC 6962 trees: SHA-256 with the 0x00 and 0x01 prefixes, split at the
+// largest power of two smaller than the number of items, the empty tree hashing to SHA-256("").
+// They are the trees of a Hasher configured WithSHA256.
+
+// TendermintProof has the shape of the Proof of tendermint/crypto/merkle: the aunts are the
+// hashes of the audit path from the leaf up, their sides following from the index and the total.
+type TendermintProof struct {
+	Total    int64
+	Index    int64
+	LeafHash []byte
+	Aunts    [][]byte
+}
+
+// TendermintRoot returns the simple merkle root of items, as HashFromByteSlices does.
+func TendermintRoot(items [][]byte) []byte {
+	return namedHashers[HashSHA256].Root(items)
+}
+
+// NewTendermintProof returns the proof of the item at index i, as ProofsFromByteSlices does.
+// This errors like Proof.
+func NewTendermintProof(items [][]byte, i int) (TendermintProof, error) {
+	h := namedHashers[HashSHA256]
+	p, err := h.Prove(items, i)
+	if err != nil {
+		return TendermintProof{}, err
+	}
+	return ToTendermintProof(p, h.LeafHash(items[i]))
+}
+
+// ToTendermintProof converts an inclusion proof of a Hasher configured WithSHA256 and the leaf hash
+// of the proven item into a TendermintProof.
+// This errors like InclusionProof.Compact.
+func ToTendermintProof(p InclusionProof, leafHash []byte) (TendermintProof, error) {
+	hashes, index, total, err := ToTrillianProof(p)
+	if err != nil {
+		return TendermintProof{}, err
+	}
+	return TendermintProof{Total: total, Index: index, LeafHash: leafHash, Aunts: hashes}, nil
+}
+
+// FromTendermintProof converts a TendermintProof into an inclusion proof for a Hasher configured WithSHA256.
+// This errors like FromTrillianProof.
+func FromTendermintProof(p TendermintProof) (InclusionProof, error) {
+	return FromTrillianProof(p.Aunts, p.Index, p.Total)
+}
+
+// Verify verifies that leaf is the item of the proof in the simple merkle tree whose root is root.
+// This errors with ErrInvalidLeaf when the leaf hash of the proof is not the one of leaf,
+// and otherwise like FromTendermintProof and InclusionProof.Verify.
+func (p TendermintProof) Verify(root, leaf []byte) error {
+	h := namedHashers[HashSHA256]
+	if !equalDigest(p.LeafHash, h.LeafHash(leaf)) {
+		return fmt.Errorf("%w: leaf hash does not match the proof", ErrInvalidLeaf)
+	}
+	q, err := FromTendermintProof(p)
+	if err != nil {
+		return err
+	}
+	return h.VerifyInclusion(root, leaf, q)
+}
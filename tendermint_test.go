@@ -0,0 +1,56 @@
+package merkle
+
+import (
+	"errors"
+	"testing"
+)
+
+// The vectors of the tests of crypto/merkle in CometBFT for HashFromByteSlices and its RFC 6962
+// leaf and inner hashes.
+func TestTendermintVectors(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		items [][]byte
+		want  string
+	}{
+		{"nil", nil, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"empty", [][]byte{}, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"single nil", [][]byte{nil}, "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d"},
+		{"single blank", [][]byte{{}}, "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d"},
+		{"single byte", [][]byte{{1}}, "b413f47d13ee2fe6c845b2ee141af81de858df4ec549a58b7970bb96645bc8d2"},
+		{"leaf", [][]byte{[]byte("L123456")}, "395aa064aa4c29f7010acfe3f25db9485bbd4b91897b6ad7ad547639252b4d56"},
+	} {
+		if got := hexify(TendermintRoot(c.items)); got != c.want {
+			t.Errorf("%v: root = %v, want %v", c.name, got, c.want)
+		}
+	}
+	const inner = "aa217fe888e47007fa15edab33c2b492a722cb106c64667fc2b044444de66bbb"
+	if got := hexify(NewHasher(WithSHA256()).NodeHash([]byte("N123"), []byte("N456"))); got != inner {
+		t.Errorf("inner hash = %v, want %v", got, inner)
+	}
+}
+
+func TestTendermintProofs(t *testing.T) {
+	items := rfc6962Items(t)
+	for n := 1; n <= len(items); n++ {
+		root := TendermintRoot(items[:n])
+		if got := hexify(root); got != rfc6962Roots[n] {
+			t.Fatalf("root of %v items = %v, want %v", n, got, rfc6962Roots[n])
+		}
+		for i := 0; i < n; i++ {
+			p, err := NewTendermintProof(items[:n], i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Total != int64(n) || p.Index != int64(i) || len(p.Aunts) != ProofLen(i, n) {
+				t.Errorf("proof of %v in %v items has total %v, index %v and %v aunts", i, n, p.Total, p.Index, len(p.Aunts))
+			}
+			if err := p.Verify(root, items[i]); err != nil {
+				t.Errorf("proof of %v in %v items: %v", i, n, err)
+			}
+			if err := p.Verify(root, []byte("other")); !errors.Is(err, ErrInvalidLeaf) {
+				t.Errorf("proof of %v in %v items for another leaf: got %v, want ErrInvalidLeaf", i, n, err)
+			}
+		}
+	}
+}
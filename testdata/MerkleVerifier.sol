@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// MerkleVerifier verifies the proofs of trees built with WithKeccak256, taking the calldata
+// produced by EncodeSolidityProofABI after the root and the item.
+contract MerkleVerifier {
+    function verify(bytes32 root, bytes calldata item, bytes32[] calldata proof, uint256 directions)
+        external
+        pure
+        returns (bool)
+    {
+        bytes32 node = keccak256(abi.encodePacked(bytes1(0x00), item));
+        for (uint256 i = 0; i < proof.length; i++) {
+            node = (directions >> i) & 1 == 1
+                ? keccak256(abi.encodePacked(bytes1(0x01), proof[i], node))
+                : keccak256(abi.encodePacked(bytes1(0x01), node, proof[i]));
+        }
+        return node == root;
+    }
+}
@@ -0,0 +1,481 @@
+package merkle
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Tree is a merkle tree that keeps every node hash in memory so that the root
+// and the audit paths can be read without rehashing the items.
+//
+// Nodes are stored level by level, levels[0] holding the leaf hashes and the
+// last level holding the root. When a level has an odd number of nodes the last
+// one has no sibling and is carried up unchanged, which yields exactly the same
+// shape as the prevPowerOfTwo split used by Root and Proof.
+//
+// A Tree is safe for concurrent use: readers share a read lock and Append and Update
+// take the write lock, so every root and audit path returned is the one of the tree
+// either before or after a concurrent write, never of a partially updated tree.
+// Nodes are replaced rather than modified in place, so the hashes returned stay valid
+// after later writes.
+type Tree struct {
+	mu     sync.RWMutex
+	h      *Hasher
+	levels [][][]byte
+	leaves map[string][]int // indices of each leaf hash, in increasing order, nil WithoutLeafIndex
+
+	versions []treeVersion
+}
+
+// NewTree hashes the items once and returns a tree holding all of its nodes.
+// The options configure the hash function as for NewHasher.
+func NewTree(items [][]byte, opts ...Option) *Tree {
+	return NewHasher(opts...).NewTree(items)
+}
+
+// NewTree hashes the items once using the Hasher's hash function
+// and returns a tree holding all of its nodes.
+func (h *Hasher) NewTree(items [][]byte) *Tree {
+	t, _ := h.newTree(context.Background(), items)
+	return t
+}
+
+// newTree is NewTree returning ctx.Err() and no tree as soon as it sees ctx is done.
+func (h *Hasher) newTree(ctx context.Context, items [][]byte) (*Tree, error) {
+	p := h.newProgress(ctx, 2*len(items)-1)
+	level := make([][]byte, len(items))
+	for i, item := range items {
+		level[i] = h.LeafHashAt(i, item)
+		if err := p.step(); err != nil {
+			return nil, err
+		}
+	}
+	return h.buildTree(p, level, nil)
+}
+
+// fromLeafHashes builds the interior nodes of a tree over the given leaf hashes,
+// the tree keeps the slice as its first level.
+func (h *Hasher) fromLeafHashes(level [][]byte) *Tree {
+	return h.fromLeafHashesReusing(level, nil)
+}
+
+// fromLeafHashesReusing is fromLeafHashes taking the interior nodes reuse returns instead of
+// hashing them, reuse returning nil for the nodes it does not know. A nil reuse knows no node.
+func (h *Hasher) fromLeafHashesReusing(level [][]byte, reuse func(level, index int) []byte) *Tree {
+	t, _ := h.buildTree(h.newProgress(context.Background(), len(level)-1), level, reuse)
+	return t
+}
+
+// buildTree is fromLeafHashesReusing counting each interior node with p, returning the error of
+// p and no tree as soon as it sees the context of p is done.
+func (h *Hasher) buildTree(p *progress, level [][]byte, reuse func(level, index int) []byte) (*Tree, error) {
+	t := &Tree{h: h}
+	if !h.noLeafIndex {
+		t.leaves = make(map[string][]int, len(level))
+		for i, node := range level {
+			t.leaves[string(node)] = append(t.leaves[string(node)], i)
+		}
+	}
+	if len(level) == 0 {
+		return t, nil
+	}
+
+	t.levels = append(t.levels, level)
+
+	for len(level) > 1 {
+		next := make([][]byte, (len(level)+1)/2)
+		for i := range next {
+			if 2*i+1 == len(level) {
+				next[i] = level[2*i]
+				continue
+			}
+			if reuse != nil {
+				next[i] = reuse(len(t.levels), i)
+			}
+			if next[i] == nil {
+				next[i] = h.NodeHash(level[2*i], level[2*i+1])
+			}
+			if err := p.step(); err != nil {
+				return nil, err
+			}
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+
+	return t, nil
+}
+
+// Root returns the root hash of the tree.
+func (t *Tree) Root() []byte {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.root()
+}
+
+func (t *Tree) root() []byte {
+	if len(t.levels) == 0 {
+		return t.h.emptyHash()
+	}
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Proof returns the audit path for the item at index i, in the same order as
+// the package level Proof function.
+// This errors with ErrEmptyTree when the tree has no items and ErrIndexOutOfBounds
+// when the requested index is out of bounds.
+func (t *Tree) Proof(i int) ([]AuditHash, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.proof(i)
+}
+
+func (t *Tree) proof(i int) ([]AuditHash, error) {
+	if len(t.levels) == 0 {
+		return nil, ErrEmptyTree
+	}
+	if i < 0 || i >= len(t.levels[0]) {
+		return nil, indexError(i, len(t.levels[0]))
+	}
+
+	res := []AuditHash{}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := i ^ 1
+		if sibling < len(level) {
+			res = append(res, AuditHash{level[sibling], sibling > i})
+		}
+		i /= 2
+	}
+	return res, nil
+}
+
+// Append adds a leaf at the end of the tree and returns its index.
+// Only the nodes on the right edge of the tree, O(log n) of them, are rehashed.
+func (t *Tree) Append(leaf []byte) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.levels) == 0 {
+		t.levels = [][][]byte{{}}
+	}
+	index := len(t.levels[0])
+	t.levels[0] = append(t.levels[0], t.h.LeafHashAt(index, leaf))
+	t.insertLeaf(string(t.levels[0][index]), index)
+
+	i := index
+	for k := 0; len(t.levels[k]) > 1; k++ {
+		level := t.levels[k]
+		parent := level[i]
+		if i%2 == 1 {
+			parent = t.h.NodeHash(level[i-1], level[i])
+		}
+
+		if k+1 == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+		i /= 2
+		if i < len(t.levels[k+1]) {
+			t.levels[k+1][i] = parent
+		} else {
+			t.levels[k+1] = append(t.levels[k+1], parent)
+		}
+	}
+	return index
+}
+
+// AppendAll adds the leaves at the end of the tree and returns the index of the first one.
+// Each node on the right of the first new leaf is hashed once, rather than once per leaf for
+// repeated calls to Append, and the tree is the same as after appending the leaves one at a time.
+func (t *Tree) AppendAll(leaves [][]byte) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	start := t.leafCount()
+	if len(leaves) == 0 {
+		return start
+	}
+	if len(t.levels) == 0 {
+		t.levels = [][][]byte{{}}
+	}
+	for i, leaf := range leaves {
+		t.levels[0] = append(t.levels[0], t.h.LeafHashAt(start+i, leaf))
+		t.insertLeaf(string(t.levels[0][start+i]), start+i)
+	}
+
+	// The parents from the one of the first changed node onwards are hashed again.
+	i := start
+	for k := 0; len(t.levels[k]) > 1; k++ {
+		level := t.levels[k]
+		if k+1 == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+		i /= 2
+		next := t.levels[k+1][:i]
+		for j := i; 2*j < len(level); j++ {
+			if 2*j+1 < len(level) {
+				next = append(next, t.h.NodeHash(level[2*j], level[2*j+1]))
+			} else {
+				next = append(next, level[2*j])
+			}
+		}
+		t.levels[k+1] = next
+	}
+	return start
+}
+
+// Update replaces the leaf at index i and rehashes the O(log n) nodes on its path to the root,
+// the new root is then returned by Root.
+// This errors with ErrEmptyTree when the tree has no items and ErrIndexOutOfBounds
+// when the index is out of bounds.
+func (t *Tree) Update(i int, data []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.levels) == 0 {
+		return ErrEmptyTree
+	}
+	if i < 0 || i >= len(t.levels[0]) {
+		return indexError(i, len(t.levels[0]))
+	}
+
+	t.removeLeaf(string(t.levels[0][i]), i)
+	t.levels[0][i] = t.h.LeafHashAt(i, data)
+	t.insertLeaf(string(t.levels[0][i]), i)
+	for k := 0; k < len(t.levels)-1; k++ {
+		level := t.levels[k]
+		parent := level[i]
+		if sibling := i ^ 1; sibling < len(level) {
+			if sibling > i {
+				parent = t.h.NodeHash(level[i], level[sibling])
+			} else {
+				parent = t.h.NodeHash(level[sibling], level[i])
+			}
+		}
+		i /= 2
+		t.levels[k+1][i] = parent
+	}
+	return nil
+}
+
+// ProofAll returns the audit paths of every item, building the tree only once.
+// This errors with ErrEmptyTree when there are no items.
+func ProofAll(items [][]byte) ([][]AuditHash, error) {
+	return defaultHasher.ProofAll(items)
+}
+
+// ProofAll returns the audit paths of every item using the Hasher's hash function.
+// The paths share their hashes with each other rather than holding copies.
+// This errors with ErrEmptyTree when there are no items.
+func (h *Hasher) ProofAll(items [][]byte) ([][]AuditHash, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyTree
+	}
+
+	t := h.NewTree(items)
+	paths, _ := t.proofAll(context.Background())
+	return paths, nil
+}
+
+// proofAll returns the audit paths of every leaf of the tree, returning ctx.Err() as soon as it sees ctx is done.
+func (t *Tree) proofAll(ctx context.Context) ([][]AuditHash, error) {
+	paths := make([][]AuditHash, t.leafCount())
+	for i := range paths {
+		if i%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		path, err := t.proof(i)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = path
+	}
+	return paths, nil
+}
+
+// ProofByLeaf returns the index and audit path of the first item equal to leaf.
+// This errors with ErrLeafNotFound when no item is equal to leaf.
+func ProofByLeaf(items [][]byte, leaf []byte) (int, []AuditHash, error) {
+	return defaultHasher.ProofByLeaf(items, leaf)
+}
+
+// ProofByLeaf returns the index and audit path of the first item equal to leaf
+// using the Hasher's hash function.
+// This errors with ErrLeafNotFound when no item is equal to leaf.
+func (h *Hasher) ProofByLeaf(items [][]byte, leaf []byte) (int, []AuditHash, error) {
+	for i, item := range items {
+		if bytes.Equal(item, leaf) {
+			path, err := h.Proof(items, i)
+			return i, path, err
+		}
+	}
+	return 0, nil, ErrLeafNotFound
+}
+
+// ProofByLeaf returns the index and audit path of the first leaf of the tree whose leaf hash is
+// the one of leaf, the index is found in constant time unless the tree was built WithoutLeafIndex.
+// This errors with ErrLeafNotFound when the leaf is not in the tree.
+func (t *Tree) ProofByLeaf(leaf []byte) (int, []AuditHash, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	indices := t.indicesOfLeaf(leaf)
+	if len(indices) == 0 {
+		return 0, nil, ErrLeafNotFound
+	}
+	path, err := t.proof(indices[0])
+	return indices[0], path, err
+}
+
+// indicesOf returns the indices of the leaves with the given leaf hash, in increasing order.
+func (t *Tree) indicesOf(key string) []int {
+	if t.leaves != nil || len(t.levels) == 0 {
+		return t.leaves[key]
+	}
+	var indices []int
+	for i, node := range t.levels[0] {
+		if string(node) == key {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// indicesOfLeaf returns the indices of the leaves equal to leaf, in increasing order.
+// When leaf hashes bind their index the leaf is hashed at every index instead.
+func (t *Tree) indicesOfLeaf(leaf []byte) []int {
+	if !t.h.indexedLeaves {
+		return t.indicesOf(string(t.h.LeafHash(leaf)))
+	}
+	var indices []int
+	if len(t.levels) > 0 {
+		for i, node := range t.levels[0] {
+			if equalDigest(node, t.h.LeafHashAt(i, leaf)) {
+				indices = append(indices, i)
+			}
+		}
+	}
+	return indices
+}
+
+// leafIndex returns the indices of every leaf hash, building them when the tree keeps no index.
+// The map must not be modified.
+func (t *Tree) leafIndex() map[string][]int {
+	if t.leaves != nil {
+		return t.leaves
+	}
+	leaves := make(map[string][]int)
+	if len(t.levels) > 0 {
+		for i, node := range t.levels[0] {
+			leaves[string(node)] = append(leaves[string(node)], i)
+		}
+	}
+	return leaves
+}
+
+// IndexOf returns the index of the first leaf of the tree whose leaf hash is the one of leaf,
+// and false when the leaf is not in the tree. Like ProofByLeaf it takes constant time unless the
+// tree was built WithoutLeafIndex.
+func (t *Tree) IndexOf(leaf []byte) (int, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	indices := t.indicesOfLeaf(leaf)
+	if len(indices) == 0 {
+		return 0, false
+	}
+	return indices[0], true
+}
+
+// ProofFor returns the inclusion proof of the first leaf of the tree whose leaf hash is the one of leaf.
+// This errors with ErrLeafNotFound when the leaf is not in the tree.
+func (t *Tree) ProofFor(leaf []byte) (InclusionProof, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	indices := t.indicesOfLeaf(leaf)
+	if len(indices) == 0 {
+		return InclusionProof{}, ErrLeafNotFound
+	}
+	path, err := t.proof(indices[0])
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	return InclusionProof{LeafIndex: indices[0], TreeSize: t.leafCount(), Path: path}, nil
+}
+
+// rLockPair read locks two trees, once when they are the same, and returns the function unlocking
+// them. The locks are taken in the order of the addresses of the trees, so that two calls given
+// the same trees in opposite orders cannot each hold one lock while writers queue on the other.
+func rLockPair(a, b *Tree) func() {
+	if a == b {
+		a.mu.RLock()
+		return a.mu.RUnlock
+	}
+	if reflect.ValueOf(a).Pointer() > reflect.ValueOf(b).Pointer() {
+		a, b = b, a
+	}
+	a.mu.RLock()
+	b.mu.RLock()
+	return func() {
+		b.mu.RUnlock()
+		a.mu.RUnlock()
+	}
+}
+
+// insertLeaf records that the leaf at index i has the given leaf hash.
+func (t *Tree) insertLeaf(key string, i int) {
+	if t.leaves == nil {
+		return
+	}
+	indices := t.leaves[key]
+	j := sort.SearchInts(indices, i)
+	indices = append(indices, 0)
+	copy(indices[j+1:], indices[j:])
+	indices[j] = i
+	t.leaves[key] = indices
+}
+
+// removeLeaf forgets that the leaf at index i has the given leaf hash.
+func (t *Tree) removeLeaf(key string, i int) {
+	if t.leaves == nil {
+		return
+	}
+	indices := t.leaves[key]
+	j := sort.SearchInts(indices, i)
+	if j == len(indices) || indices[j] != i {
+		return
+	}
+	indices = append(indices[:j], indices[j+1:]...)
+	if len(indices) == 0 {
+		delete(t.leaves, key)
+		return
+	}
+	t.leaves[key] = indices
+}
+
+// Visit calls fn for every node of the tree in level order, from the leaves up and from left to
+// right within a level, with the level of the node, its index within the level, a copy of its hash
+// and whether it is a leaf. Levels are counted from the leaves, 0 being the leaf level.
+// A node without a sibling is carried up unchanged rather than hashed, so it is only visited
+// once, at the level where it was created. The walk stops as soon as fn returns false.
+// The tree is read locked during the walk, fn must not call its methods.
+func (t *Tree) Visit(fn func(level, index int, hash []byte, isLeaf bool) bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for k, level := range t.levels {
+		for i, node := range level {
+			if k > 0 && 2*i+1 >= len(t.levels[k-1]) {
+				continue
+			}
+			if !fn(k, i, append([]byte(nil), node...), k == 0) {
+				return
+			}
+		}
+	}
+}
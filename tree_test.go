@@ -0,0 +1,73 @@
+package merkle
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestTreeConcurrentAppend reads proofs while leaves are appended, run it with -race. The tree
+// only grows, so a proof read at a size verifies against the root at that size read later.
+func TestTreeConcurrentAppend(t *testing.T) {
+	const n, readers = 512, 4
+	tr := NewTree(nil)
+	leaf := func(i int) []byte { return []byte("leaf " + strconv.Itoa(i)) }
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	errs := make(chan error, readers)
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			for k := 0; ; k++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				size := tr.LeafCount()
+				if size == 0 {
+					continue
+				}
+				i := (k*7 + r) % size
+				p, err := tr.ProofAtSize(i, size)
+				if err == nil {
+					var root []byte
+					if root, err = tr.RootAtSize(size); err == nil {
+						err = p.Verify(root, leaf(i))
+					}
+				}
+				if err == nil {
+					_, err = tr.ProofFor(leaf(i))
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+				_ = tr.Root()
+			}
+		}(r)
+	}
+	for i := 0; i < n; i++ {
+		if i%64 == 63 {
+			tr.AppendAll([][]byte{leaf(i)})
+		} else {
+			tr.Append(leaf(i))
+		}
+	}
+	close(done)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	items := make([][]byte, n)
+	for i := range items {
+		items[i] = leaf(i)
+	}
+	if !equalDigest(tr.Root(), Root(items)) {
+		t.Fatal("root after the concurrent appends is not the root of the items")
+	}
+}
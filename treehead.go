@@ -0,0 +1,131 @@
+package merkle
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// treeHeadSize is the size of the fields of an encoded tree head before its root.
+const treeHeadSize = 8 + 8 + 1
+
+// TreeHead is a statement binding the root of a tree to its size at a point in time, like the
+// signed tree heads of Certificate Transparency logs. Its encoding keeps the timestamp to the
+// millisecond, finer timestamps are truncated before signing.
+type TreeHead struct {
+	Size      uint64
+	Root      []byte
+	Timestamp time.Time
+}
+
+// TreeHead returns the head of the tree at the current time, truncated to the millisecond.
+func (t *Tree) TreeHead() TreeHead {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return TreeHead{
+		Size:      uint64(t.leafCount()),
+		Root:      append([]byte(nil), t.root()...),
+		Timestamp: time.UnixMilli(time.Now().UnixMilli()).UTC(),
+	}
+}
+
+// MarshalBinary encodes the tree head in the envelope of the package with the following payload,
+// which is what SignTreeHead signs:
+//
+//	uint64  tree size, big endian
+//	int64   timestamp in milliseconds since the Unix epoch, big endian
+//	uint8   size of the root in bytes
+//	root
+//
+// This errors with ErrMalformedTreeHead when the root is empty or longer than 255 bytes.
+func (th TreeHead) MarshalBinary() ([]byte, error) {
+	if len(th.Root) == 0 || len(th.Root) > maxHashSize {
+		return nil, fmt.Errorf("%w: unsupported root size %v", ErrMalformedTreeHead, len(th.Root))
+	}
+	data := make([]byte, treeHeadSize, treeHeadSize+len(th.Root))
+	binary.BigEndian.PutUint64(data, th.Size)
+	binary.BigEndian.PutUint64(data[8:], uint64(th.Timestamp.UnixMilli()))
+	data[16] = byte(len(th.Root))
+	return seal(kindTreeHead, append(data, th.Root...)), nil
+}
+
+// UnmarshalBinary decodes a tree head encoded by MarshalBinary, its timestamp being in UTC.
+// This errors with ErrUnknownFormat or ErrUnknownFormatVersion when the data is not a tree head of
+// this format version and with ErrMalformedTreeHead when it is truncated or has trailing bytes.
+func (th *TreeHead) UnmarshalBinary(data []byte) error {
+	data, err := unseal(kindTreeHead, data)
+	if err != nil {
+		return err
+	}
+	if len(data) < treeHeadSize {
+		return fmt.Errorf("%w: truncated header", ErrMalformedTreeHead)
+	}
+	size := int(data[16])
+	if size == 0 || len(data) != treeHeadSize+size {
+		return fmt.Errorf("%w: root of %v bytes does not match %v bytes", ErrMalformedTreeHead, size, len(data)-treeHeadSize)
+	}
+	*th = TreeHead{
+		Size:      binary.BigEndian.Uint64(data),
+		Root:      append([]byte(nil), data[treeHeadSize:]...),
+		Timestamp: time.UnixMilli(int64(binary.BigEndian.Uint64(data[8:]))).UTC(),
+	}
+	return nil
+}
+
+// SignTreeHead returns the detached signature of the encoding of head by signer.
+// Nil opts sign Ed25519 keys over the encoding itself and other keys, for example ECDSA, over its
+// SHA-256 digest, which is what VerifyTreeHead checks. Other opts hash the encoding with their
+// HashFunc, if any, before signing.
+// This errors like TreeHead.MarshalBinary and with the errors of the signer.
+func SignTreeHead(head TreeHead, signer crypto.Signer, opts crypto.SignerOpts) ([]byte, error) {
+	msg, err := head.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = crypto.SHA256
+		if _, ok := signer.Public().(ed25519.PublicKey); ok {
+			opts = crypto.Hash(0)
+		}
+	}
+	if hf := opts.HashFunc(); hf != 0 {
+		if !hf.Available() {
+			return nil, fmt.Errorf("%w: hash %v is not available", ErrInvalidSignature, hf)
+		}
+		d := hf.New()
+		d.Write(msg)
+		msg = d.Sum(nil)
+	}
+	return signer.Sign(rand.Reader, msg, opts)
+}
+
+// VerifyTreeHead verifies that sig is a signature of head by the key pub, an ed25519.PublicKey
+// or an *ecdsa.PublicKey such as a P-256 key, as made by SignTreeHead with nil opts. ECDSA
+// signatures are ASN.1 encoded over the SHA-256 digest of the encoding of the head.
+// This errors with ErrInvalidSignature when the signature does not verify or the key type is not
+// supported and like TreeHead.MarshalBinary.
+func VerifyTreeHead(head TreeHead, sig []byte, pub crypto.PublicKey) error {
+	msg, err := head.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	var ok bool
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		ok = len(k) == ed25519.PublicKeySize && ed25519.Verify(k, msg, sig)
+	case *ecdsa.PublicKey:
+		d := sha256.Sum256(msg)
+		ok = ecdsa.VerifyASN1(k, d[:], sig)
+	default:
+		return fmt.Errorf("%w: unsupported public key %T", ErrInvalidSignature, pub)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// Trillian logs serve inclusion and consistency proofs as bare lists of RFC 6962 node hashes,
+// the sides of the hashes following from the leaf index and the tree sizes. Once converted
+// the proofs verify with a Hasher configured WithSHA256 against the root of the log.
+
+// FromTrillianProof converts the hashes of a Trillian inclusion proof of the leaf at leafIndex
+// in a tree of treeSize leaves into an InclusionProof.
+// This errors with ErrIndexOutOfBounds or ErrBadPathLength when the proof does not fit a tree of its size
+// and with ErrMalformedProof when a hash is not a SHA-256 digest.
+func FromTrillianProof(hashes [][]byte, leafIndex, treeSize int64) (InclusionProof, error) {
+	if int64(int(leafIndex)) != leafIndex || int64(int(treeSize)) != treeSize {
+		return InclusionProof{}, fmt.Errorf("%w: index %v, tree size %v", ErrIndexOutOfBounds, leafIndex, treeSize)
+	}
+	if err := checkTrillianHashes(hashes); err != nil {
+		return InclusionProof{}, err
+	}
+	return CompactProof{LeafIndex: int(leafIndex), TreeSize: int(treeSize), Hashes: hashes}.Expand()
+}
+
+// ToTrillianProof returns the hashes, leaf index and tree size of an inclusion proof as Trillian serves them.
+// This errors like InclusionProof.Compact.
+func ToTrillianProof(p InclusionProof) (hashes [][]byte, leafIndex, treeSize int64, err error) {
+	c, err := p.Compact()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return c.Hashes, int64(c.LeafIndex), int64(c.TreeSize), nil
+}
+
+// FromTrillianConsistencyProof converts the hashes of a Trillian consistency proof between
+// trees of oldSize and newSize leaves into a proof for VerifyConsistency.
+// This errors with ErrIndexOutOfBounds when the sizes are not ordered, ErrBadPathLength when
+// the number of hashes does not match the sizes and ErrMalformedProof when a hash is not a SHA-256 digest.
+func FromTrillianConsistencyProof(hashes [][]byte, oldSize, newSize int64) ([]AuditHash, error) {
+	if oldSize < 0 || oldSize > newSize || int64(int(newSize)) != newSize {
+		return nil, fmt.Errorf("%w: old size %v, new size %v", ErrIndexOutOfBounds, oldSize, newSize)
+	}
+	if err := checkTrillianHashes(hashes); err != nil {
+		return nil, err
+	}
+	sides := []bool{}
+	if oldSize != 0 && oldSize != newSize {
+		sides = subproofSides(int(oldSize), int(newSize), true)
+	}
+	if len(hashes) != len(sides) {
+		return nil, fmt.Errorf("%w: %v hashes for sizes %v and %v, expected %v", ErrBadPathLength, len(hashes), oldSize, newSize, len(sides))
+	}
+	proof := make([]AuditHash, len(hashes))
+	for j, right := range sides {
+		proof[j] = AuditHash{hashes[j], right}
+	}
+	return proof, nil
+}
+
+// ToTrillianConsistencyProof returns the hashes of a consistency proof as Trillian serves them.
+func ToTrillianConsistencyProof(proof []AuditHash) [][]byte {
+	hashes := make([][]byte, len(proof))
+	for j, p := range proof {
+		hashes[j] = p.Val
+	}
+	return hashes
+}
+
+// subproofSides returns the RightOperator of each entry of SUBPROOF(m, D[n], complete),
+// following the recursion of Hasher.subproof.
+func subproofSides(m, n int, complete bool) []bool {
+	if m == n {
+		if complete {
+			return []bool{}
+		}
+		return []bool{false}
+	}
+	k := prevPowerOfTwo(n)
+	if m <= k {
+		return append(subproofSides(m, k, complete), true)
+	}
+	return append(subproofSides(m-k, n-k, false), false)
+}
+
+func checkTrillianHashes(hashes [][]byte) error {
+	for j, h := range hashes {
+		if len(h) != sha256.Size {
+			return fmt.Errorf("%w: hash %v has size %v, expected %v", ErrMalformedProof, j, len(h), sha256.Size)
+		}
+	}
+	return nil
+}
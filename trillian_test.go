@@ -0,0 +1,102 @@
+package merkle
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// trillianFixture is testdata/trillian_proofs.json, the int64 fields of protojson being strings and
+// the hashes base64.
+type trillianFixture struct {
+	Leaves    []string          `json:"leaves"`
+	Roots     map[string][]byte `json:"roots"`
+	Inclusion []struct {
+		TreeSize int64 `json:"treeSize,string"`
+		Proof    struct {
+			LeafIndex int64    `json:"leafIndex,string"`
+			Hashes    [][]byte `json:"hashes"`
+		} `json:"proof"`
+	} `json:"inclusion"`
+	Consistency []struct {
+		FirstTreeSize  int64 `json:"firstTreeSize,string"`
+		SecondTreeSize int64 `json:"secondTreeSize,string"`
+		Proof          struct {
+			Hashes [][]byte `json:"hashes"`
+		} `json:"proof"`
+	} `json:"consistency"`
+}
+
+func loadTrillianFixture(t *testing.T) (*trillianFixture, [][]byte) {
+	data, err := os.ReadFile("testdata/trillian_proofs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f trillianFixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	items := make([][]byte, len(f.Leaves))
+	for i, leaf := range f.Leaves {
+		items[i] = mustHex(t, leaf)
+	}
+	return &f, items
+}
+
+func TestTrillianInclusionProofs(t *testing.T) {
+	f, items := loadTrillianFixture(t)
+	h := NewHasher(WithSHA256())
+	for _, c := range f.Inclusion {
+		root := f.Roots[strconv.FormatInt(c.TreeSize, 10)]
+		p, err := FromTrillianProof(c.Proof.Hashes, c.Proof.LeafIndex, c.TreeSize)
+		if err != nil {
+			t.Fatalf("FromTrillianProof(%v, %v): %v", c.Proof.LeafIndex, c.TreeSize, err)
+		}
+		if err := h.VerifyInclusion(root, items[c.Proof.LeafIndex], p); err != nil {
+			t.Errorf("proof of %v in %v leaves: %v", c.Proof.LeafIndex, c.TreeSize, err)
+		}
+		mine, err := h.Prove(items[:c.TreeSize], int(c.Proof.LeafIndex))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes, index, size, err := ToTrillianProof(mine)
+		if err != nil || index != c.Proof.LeafIndex || size != c.TreeSize || !equalHashes(hashes, c.Proof.Hashes) {
+			t.Errorf("ToTrillianProof(%v, %v) = %x, %v, %v, %v", c.Proof.LeafIndex, c.TreeSize, hashes, index, size, err)
+		}
+	}
+}
+
+func TestTrillianConsistencyProofs(t *testing.T) {
+	f, items := loadTrillianFixture(t)
+	h := NewHasher(WithSHA256())
+	for _, c := range f.Consistency {
+		oldRoot, newRoot := f.Roots[strconv.FormatInt(c.FirstTreeSize, 10)], f.Roots[strconv.FormatInt(c.SecondTreeSize, 10)]
+		proof, err := FromTrillianConsistencyProof(c.Proof.Hashes, c.FirstTreeSize, c.SecondTreeSize)
+		if err != nil {
+			t.Fatalf("FromTrillianConsistencyProof(%v, %v): %v", c.FirstTreeSize, c.SecondTreeSize, err)
+		}
+		if !h.VerifyConsistency(oldRoot, newRoot, int(c.FirstTreeSize), int(c.SecondTreeSize), proof) {
+			t.Errorf("consistency of %v and %v leaves does not verify", c.FirstTreeSize, c.SecondTreeSize)
+		}
+		mine, err := h.ConsistencyProof(items[:c.SecondTreeSize], int(c.FirstTreeSize))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ToTrillianConsistencyProof(mine); !equalHashes(got, c.Proof.Hashes) {
+			t.Errorf("ToTrillianConsistencyProof(%v, %v) = %x, want %x", c.FirstTreeSize, c.SecondTreeSize, got, c.Proof.Hashes)
+		}
+	}
+}
+
+func equalHashes(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !equalDigest(a[k], b[k]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package merkle
+
+import "fmt"
+
+// Verifier folds an audit path one entry at a time, as the entries arrive, in constant memory.
+// Adding the entries of a path returned by Proof and checking the root is equivalent to VerifyProof,
+// except that the index is not checked against the length of the path.
+type Verifier struct {
+	h   *Hasher
+	d   []byte
+	n   int // entries added
+	err error
+}
+
+// NewVerifier returns a Verifier starting from leaf, the options configure the hash function as for NewHasher.
+func NewVerifier(leaf []byte, opts ...Option) *Verifier {
+	return NewHasher(opts...).NewVerifier(leaf)
+}
+
+// NewVerifier returns a Verifier starting from leaf using the Hasher's hash function.
+// The leaf is hashed with LeafHash, leaves of a Hasher binding leaf indices are verified with
+// NewLeafHashVerifier and LeafHashAt.
+func (h *Hasher) NewVerifier(leaf []byte) *Verifier {
+	v := &Verifier{h: h, d: make([]byte, 0, h.Size())}
+	v.Reset(leaf)
+	return v
+}
+
+// NewLeafHashVerifier returns a Verifier starting from the hash of a leaf using the Hasher's hash function.
+// A leaf hash that does not have the digest size makes every check fail.
+func (h *Hasher) NewLeafHashVerifier(leafHash []byte) *Verifier {
+	v := &Verifier{h: h}
+	if len(leafHash) != h.Size() {
+		v.err = fmt.Errorf("%w: leaf hash has size %v, expected %v", ErrBadHashSize, len(leafHash), h.Size())
+		return v
+	}
+	v.d = append(make([]byte, 0, h.Size()), leafHash...)
+	return v
+}
+
+// Reset restarts the Verifier from leaf, reusing its buffer.
+// A leaf exceeding the Hasher's Limits is not hashed and makes every check fail.
+func (v *Verifier) Reset(leaf []byte) {
+	v.n = 0
+	if v.err = v.h.checkLeafSize(len(leaf)); v.err != nil {
+		return
+	}
+	v.d = v.h.leafHashTo(v.d[:0], leaf)
+}
+
+// Add folds the next entry of the path, sibling being on the right of the path when right is true.
+// This errors with ErrBadHashSize when sibling does not have the digest size and ErrPathTooLong
+// when the entry exceeds the Hasher's Limits, after which every check fails until the Verifier
+// is Reset. Once a call failed, Add returns the same error.
+func (v *Verifier) Add(sibling []byte, right bool) error {
+	if v.err != nil {
+		return v.err
+	}
+	if v.err = v.h.checkPathLen(v.n + 1); v.err != nil {
+		return v.err
+	}
+	if len(sibling) != v.h.Size() {
+		v.err = fmt.Errorf("%w: entry has size %v, expected %v", ErrBadHashSize, len(sibling), v.h.Size())
+		return v.err
+	}
+	if right {
+		v.d = v.h.nodeHashTo(v.d[:0], v.d, sibling)
+	} else {
+		v.d = v.h.nodeHashTo(v.d[:0], sibling, v.d)
+	}
+	v.n++
+	return nil
+}
+
+// Sum returns a copy of the node reached by the entries added so far, nil after a failed Add.
+func (v *Verifier) Sum() []byte {
+	if v.err != nil {
+		return nil
+	}
+	return append([]byte(nil), v.d...)
+}
+
+// Check reports whether the entries added so far lead to root.
+// Like VerifyProof, it never accepts the root of the empty tree.
+func (v *Verifier) Check(root []byte) bool {
+	if v.err != nil || len(root) != v.h.Size() || equalDigest(root, v.h.empty) {
+		return false
+	}
+	return equalDigest(root, v.d)
+}
@@ -0,0 +1,55 @@
+package merkle
+
+import "fmt"
+
+// treeVersion records a snapshot of a tree: its size and the roots of the perfect subtrees
+// its leaves decompose into, largest first, which is all that is needed for its root.
+type treeVersion struct {
+	size     int
+	frontier [][]byte
+}
+
+// Snapshot records the current state of the tree and returns its version, versions being
+// numbered from 0 in the order they are taken. Only O(log n) hashes are kept per version.
+func (t *Tree) Snapshot() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := t.leafCount()
+	v := treeVersion{size: n, frontier: [][]byte{}}
+	offset := 0
+	for k := len(t.levels) - 1; k >= 0; k-- {
+		if n&(1<<uint(k)) != 0 {
+			// The perfect subtree of 2^k leaves starting at offset.
+			v.frontier = append(v.frontier, t.levels[k][offset>>uint(k)])
+			offset += 1 << uint(k)
+		}
+	}
+	t.versions = append(t.versions, v)
+	return len(t.versions) - 1
+}
+
+// RootAtVersion returns the root hash of the tree when the version v was taken.
+// This errors with ErrIndexOutOfBounds when no such version was taken.
+func (t *Tree) RootAtVersion(v int) ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if v < 0 || v >= len(t.versions) {
+		return nil, fmt.Errorf("%w: version %v, tree has %v versions", ErrIndexOutOfBounds, v, len(t.versions))
+	}
+	return t.h.bagFrontier(t.versions[v].frontier), nil
+}
+
+// SizeAtVersion returns the number of leaves of the tree when the version v was taken,
+// the size to give ConsistencyProof and VerifyConsistency for that version.
+// This errors with ErrIndexOutOfBounds when no such version was taken.
+func (t *Tree) SizeAtVersion(v int) (int, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if v < 0 || v >= len(t.versions) {
+		return 0, fmt.Errorf("%w: version %v, tree has %v versions", ErrIndexOutOfBounds, v, len(t.versions))
+	}
+	return t.versions[v].size, nil
+}